@@ -0,0 +1,301 @@
+package eywa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func Aggregate[M Model, MP ModelPtr[M]]() AggregateQueryBuilder[M] {
+	return AggregateQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+		},
+	}
+}
+
+type AggregateQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (aq AggregateQueryBuilder[M]) DistinctOn(f FieldName[M], fs ...FieldName[M]) AggregateQueryBuilder[M] {
+	aq.distinctOn = &distinctOn[M]{append([]FieldName[M]{f}, fs...)}
+	return aq
+}
+
+func (aq AggregateQueryBuilder[M]) Offset(n int) AggregateQueryBuilder[M] {
+	aq.offset = (*offset)(&n)
+	return aq
+}
+
+func (aq AggregateQueryBuilder[M]) Limit(n int) AggregateQueryBuilder[M] {
+	aq.limit = (*limit)(&n)
+	return aq
+}
+
+func (aq AggregateQueryBuilder[M]) OrderBy(o ...OrderByExpr) AggregateQueryBuilder[M] {
+	orderByArr := orderBy(o)
+	aq.orderBy = &orderByArr
+	return aq
+}
+
+func (aq AggregateQueryBuilder[M]) Where(w *WhereExpr) AggregateQueryBuilder[M] {
+	aq.where = &where{w}
+	return aq
+}
+
+func (aq AggregateQueryBuilder[M]) MarshalGQL() string {
+	return aq.marshalGQL(nil)
+}
+
+func (aq AggregateQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s_aggregate%s",
+		aq.QuerySkeleton.ModelName,
+		aq.queryArgs.marshalGQL(vs),
+	)
+}
+
+func (aq AggregateQueryBuilder[M]) Select(fn AggregateFunc[M], fns ...AggregateFunc[M]) AggregateQuery[M] {
+	return AggregateQuery[M]{
+		aq:  &aq,
+		fns: append(fns, fn),
+	}
+}
+
+// AggregateFunc is an aggregate function selected in an aggregate query.
+type AggregateFunc[M Model] string
+
+// Count selects the number of rows matched by the aggregate query.
+func Count[M Model]() AggregateFunc[M] {
+	return "count"
+}
+
+// CountColumns selects the number of rows matched by the aggregate query
+// having non null values for the given fields. It is aliased as count_<fields>,
+// eg. count_name_age, so that it can be selected along with other counts. Its
+// result is read with AggregateResult.CountOf.
+func CountColumns[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	fields = append(fields, field)
+	return AggregateFunc[M](fmt.Sprintf(
+		"count_%s: count(columns: [%s])",
+		joinFieldNamesWith(fields, "_"),
+		joinFieldNames(fields),
+	))
+}
+
+// CountDistinct selects the number of distinct values of the given fields in
+// the rows matched by the aggregate query. It is aliased as
+// count_distinct_<fields>, eg. count_distinct_name. Its result is read with
+// AggregateResult.CountOf.
+func CountDistinct[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	fields = append(fields, field)
+	return AggregateFunc[M](fmt.Sprintf(
+		"count_distinct_%s: count(columns: [%s], distinct: true)",
+		joinFieldNamesWith(fields, "_"),
+		joinFieldNames(fields),
+	))
+}
+
+// alias returns the alias of the aggregate function, if it has one.
+func (fn AggregateFunc[M]) alias() string {
+	alias, _, ok := strings.Cut(string(fn), ": ")
+	if !ok {
+		return ""
+	}
+	return alias
+}
+
+func Sum[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	return aggregateFunc("sum", append(fields, field))
+}
+
+func Avg[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	return aggregateFunc("avg", append(fields, field))
+}
+
+func Min[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	return aggregateFunc("min", append(fields, field))
+}
+
+func Max[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	return aggregateFunc("max", append(fields, field))
+}
+
+func Stddev[M Model](field FieldName[M], fields ...FieldName[M]) AggregateFunc[M] {
+	return aggregateFunc("stddev", append(fields, field))
+}
+
+func aggregateFunc[M Model](fn string, fields FieldNameArray[M]) AggregateFunc[M] {
+	return AggregateFunc[M](fmt.Sprintf("%s {\n%s\n}", fn, fields.MarshalGQL()))
+}
+
+func joinFieldNames[M Model](fields []FieldName[M]) string {
+	return joinFieldNamesWith(fields, ", ")
+}
+
+func joinFieldNamesWith[M Model](fields []FieldName[M], sep string) string {
+	stringArr := make([]string, 0, len(fields))
+	for _, f := range fields {
+		stringArr = append(stringArr, string(f))
+	}
+	return strings.Join(stringArr, sep)
+}
+
+type aggregateFuncArray[M Model] []AggregateFunc[M]
+
+func (fa aggregateFuncArray[M]) MarshalGQL() string {
+	buf := bytes.NewBufferString("")
+	for i, f := range fa {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(string(f))
+	}
+	return buf.String()
+}
+
+type AggregateQuery[M Model] struct {
+	aq    *AggregateQueryBuilder[M]
+	fns   []AggregateFunc[M]
+	nodes []Selection[M]
+}
+
+// Nodes selects the given fields of the rows matched by the aggregate query,
+// along with the aggregate functions.
+func (aq AggregateQuery[M]) Nodes(field Selection[M], fields ...Selection[M]) AggregateQuery[M] {
+	aq.nodes = append(fields, field)
+	return aq
+}
+
+func (aq AggregateQuery[M]) MarshalGQL() string {
+	return aq.marshalGQL(nil)
+}
+
+func (aq AggregateQuery[M]) marshalGQL(vs *queryVarSet) string {
+	if len(aq.nodes) == 0 {
+		return fmt.Sprintf(
+			"%s {\naggregate {\n%s\n}\n}",
+			aq.aq.marshalGQL(vs),
+			aggregateFuncArray[M](aq.fns).MarshalGQL(),
+		)
+	}
+	return fmt.Sprintf(
+		"%s {\naggregate {\n%s\n}\nnodes {\n%s\n}\n}",
+		aq.aq.marshalGQL(vs),
+		aggregateFuncArray[M](aq.fns).MarshalGQL(),
+		selectionArray[M](aq.nodes).marshalGQL(vs),
+	)
+}
+
+func (aq AggregateQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := aq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"query aggregate_%s%s {\n%s\n}",
+		aq.aq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (aq AggregateQuery[M]) Query() string {
+	return operationQuery(aq)
+}
+
+func (aq AggregateQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(aq)
+}
+
+// AggregateResult is the result of an aggregate query. Sum, Min and Max hold
+// values of the aggregated fields in the corresponding fields of M.
+type AggregateResult[M Model] struct {
+	Aggregate AggregateValues[M] `json:"aggregate"`
+	Nodes     []M                `json:"nodes"`
+}
+
+// CountOf returns the result of the count function fn, selected with Count,
+// CountColumns or CountDistinct.
+func (ar *AggregateResult[M]) CountOf(fn AggregateFunc[M]) int {
+	if alias := fn.alias(); alias != "" {
+		return ar.Aggregate.Counts[alias]
+	}
+	return ar.Aggregate.Count
+}
+
+// AggregateValues are the values of the aggregate functions selected in an
+// aggregate query. Counts holds the results of CountColumns and CountDistinct,
+// by their alias.
+type AggregateValues[M Model] struct {
+	Count  int                       `json:"count"`
+	Sum    *M                        `json:"sum"`
+	Avg    map[FieldName[M]]*float64 `json:"avg"`
+	Stddev map[FieldName[M]]*float64 `json:"stddev"`
+	Min    *M                        `json:"min"`
+	Max    *M                        `json:"max"`
+	Counts map[string]int            `json:"-"`
+}
+
+func (av *AggregateValues[M]) UnmarshalJSON(data []byte) error {
+	type aggregateValues AggregateValues[M]
+	if err := json.Unmarshal(data, (*aggregateValues)(av)); err != nil {
+		return err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	for key, value := range values {
+		if !strings.HasPrefix(key, "count_") {
+			continue
+		}
+		var count int
+		if err := json.Unmarshal(value, &count); err != nil {
+			return err
+		}
+		if av.Counts == nil {
+			av.Counts = make(map[string]int)
+		}
+		av.Counts[key] = count
+	}
+	return nil
+}
+
+func (aq AggregateQuery[M]) Exec(client *Client) (*AggregateResult[M], error) {
+	return aq.ExecWithContext(context.Background(), client)
+}
+
+func (aq AggregateQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*AggregateResult[M], error) {
+	return execQuery[M, *AggregateResult[M]](ctx, client, aq, fmt.Sprintf("%s_aggregate", aq.aq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of AggregateResult, eg. into a
+// struct with fields for aliased fields.
+func (aq AggregateQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return aq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (aq AggregateQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, aq, aq.queryRoot(), dst)
+}
+
+func (aq AggregateQuery[M]) queryRoot() string {
+	return fmt.Sprintf("%s_aggregate", aq.aq.ModelName)
+}
+
+func (aq AggregateQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (aq AggregateQuery[M]) decodeQueryResult(data json.RawMessage) (*AggregateResult[M], error) {
+	return decodeResult[*AggregateResult[M]](data)
+}
+
+// Subscribe sends the result of the aggregate query on the returned channel
+// every time it changes, until ctx is cancelled. The error ending the
+// subscription, if any, eg. ErrSubscriptionRejected or ErrHandshakeFailed, is
+// sent on the error channel. See SubscriptionClient.
+func (aq AggregateQuery[M]) Subscribe(ctx context.Context, client *SubscriptionClient) (<-chan *AggregateResult[M], <-chan error) {
+	return subscribe[M, *AggregateResult[M]](ctx, client, subscription{aq}, fmt.Sprintf("%s_aggregate", aq.aq.ModelName), nil)
+}
@@ -0,0 +1,145 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// batchQuery is implemented by the queries built with eywa which can be run in
+// a Batch, decoding their result into T.
+type batchQuery[T any] interface {
+	marshalGQL(vs *queryVarSet) string
+	queryRoot() string
+	classifyError(e GraphQLError) error
+	decodeQueryResult(data json.RawMessage) (T, error)
+}
+
+// Result is a query run as part of a Batch, created with Fetch. It holds the
+// result of the query once the batch is executed.
+type Result[T any] struct {
+	q     batchQuery[T]
+	value T
+	alias string
+	dst   interface{}
+}
+
+// Fetch adds the query q to a Batch. The result, of the type returned by the
+// Exec of q, is available from Value once the batch is executed, eg.
+//
+//	users := Fetch(Get[User]().Select(User_ID))
+//	orders := Fetch(Get[Order]().Where(...).Select(Order_ID))
+//	err := Batch(users, orders).Exec(client)
+//	users.Value() // []User
+func Fetch[T any](q batchQuery[T]) *Result[T] {
+	return &Result[T]{q: q}
+}
+
+// Value returns the result of the query, or the zero value of T if the batch
+// hasn't been executed successfully.
+func (r *Result[T]) Value() T {
+	return r.value
+}
+
+// As sets the alias of the root field of the query in the batch, instead of
+// the generated one. It panics if alias isn't a valid graphql name.
+func (r *Result[T]) As(alias string) *Result[T] {
+	r.alias = validAlias(alias)
+	return r
+}
+
+// DecodeInto decodes the result of the query into dst once the batch is
+// executed, instead of T, like the ExecInto of the query. Value then returns
+// the zero value of T.
+func (r *Result[T]) DecodeInto(dst interface{}) *Result[T] {
+	r.dst = dst
+	return r
+}
+
+func (r *Result[T]) batchField() {}
+
+func (r *Result[T]) userAlias() string {
+	return r.alias
+}
+
+func (r *Result[T]) marshalRootField(vs *queryVarSet, alias string) string {
+	return fmt.Sprintf("%s: %s", alias, r.q.marshalGQL(vs))
+}
+
+func (r *Result[T]) rootName() string {
+	return r.q.queryRoot()
+}
+
+func (r *Result[T]) classifyError(e GraphQLError) error {
+	return r.q.classifyError(e)
+}
+
+func (r *Result[T]) decode(data json.RawMessage) error {
+	if r.dst != nil {
+		return decodeInto(data, r.dst)
+	}
+	value, err := r.q.decodeQueryResult(data)
+	if err != nil {
+		return err
+	}
+	r.value = value
+	return nil
+}
+
+// BatchField is a query run as part of a Batch, created with Fetch.
+type BatchField interface {
+	rootField
+	batchField()
+}
+
+// Batch runs the queries as the root fields of a single query operation. Every
+// field is given a unique alias, made of its root field and position, eg.
+// user_1, unless one is set with As, so the same model can be queried more
+// than once. It panics if two fields have the same alias.
+func Batch(field BatchField, fields ...BatchField) BatchQuery {
+	rfs := rootFields{field}
+	for _, f := range fields {
+		rfs = append(rfs, f)
+	}
+	rfs.checkAliases()
+	return BatchQuery{rfs}
+}
+
+type BatchQuery struct {
+	fields rootFields
+}
+
+func (bq BatchQuery) MarshalGQL() string {
+	return bq.marshalGQL(nil)
+}
+
+func (bq BatchQuery) marshalGQL(vs *queryVarSet) string {
+	return bq.fields.marshalGQL(vs)
+}
+
+func (bq BatchQuery) marshalOperation(vs *queryVarSet) string {
+	body := bq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"query batch%s {\n%s\n}",
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (bq BatchQuery) Query() string {
+	return operationQuery(bq)
+}
+
+func (bq BatchQuery) Variables() map[string]interface{} {
+	return operationVariables(bq)
+}
+
+// Exec runs the batch and sets the result of every Result in it. No result is
+// set if the response contains errors.
+func (bq BatchQuery) Exec(client *Client) error {
+	return bq.ExecWithContext(context.Background(), client)
+}
+
+func (bq BatchQuery) ExecWithContext(ctx context.Context, client *Client) error {
+	return bq.fields.exec(ctx, client, bq)
+}
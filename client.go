@@ -11,14 +11,19 @@ import (
 )
 
 type Client struct {
-	endpoint   string
-	httpClient *http.Client
-	headers    map[string]string
+	endpoint         string
+	httpClient       *http.Client
+	headers          map[string]string
+	autoParameterize bool
 }
 
 type ClientOpts struct {
 	HTTPClient *http.Client
 	Headers    map[string]string
+	// AutoParameterize sends every query through Parameterize, lifting literal
+	// values into graphql variables. Queries with values which can't be lifted
+	// panic, see Parameterize.
+	AutoParameterize bool
 }
 
 // NewClient accepts a graphql endpoint and returns back a Client.
@@ -37,6 +42,8 @@ func NewClient(gqlEndpoint string, opt *ClientOpts) *Client {
 		if len(opt.Headers) > 0 {
 			c.headers = opt.Headers
 		}
+
+		c.autoParameterize = opt.AutoParameterize
 	}
 
 	return c
@@ -71,6 +78,9 @@ func (c *Client) Do(ctx context.Context, q Queryable) (*bytes.Buffer, error) {
 // Raw performs a gql query and returns the raw http response and error from the underlying http client.
 // Make sure to close the response body.
 func (c *Client) Raw(ctx context.Context, q Queryable) (*http.Response, error) {
+	if c.autoParameterize {
+		q = Parameterize(q)
+	}
 	reqObj := graphqlRequest{
 		Query:     q.Query(),
 		Variables: q.Variables(),
@@ -93,3 +103,40 @@ func (c *Client) Raw(ctx context.Context, q Queryable) (*http.Response, error) {
 
 	return c.httpClient.Do(req)
 }
+
+type graphqlResponse[T any] struct {
+	Data   map[string]T   `json:"data"`
+	Errors []GraphQLError `json:"errors"`
+}
+
+// execQuery performs a gql query and decodes the data under the root field of
+// the response. It returns a *GraphQLErrors if the response contains errors.
+func execQuery[M Model, T any](ctx context.Context, client *Client, q Queryable, root string) (T, error) {
+	var data T
+	respBytes, err := client.Do(ctx, q)
+	if err != nil {
+		return data, err
+	}
+
+	respObj := graphqlResponse[T]{}
+	err = json.NewDecoder(respBytes).Decode(&respObj)
+	if err != nil {
+		return data, err
+	}
+
+	if len(respObj.Errors) > 0 {
+		return data, newGraphQLErrors[M](respObj.Errors)
+	}
+
+	return respObj.Data[root], nil
+}
+
+// execQueryInto performs a gql query like execQuery, decoding the data under the
+// root field of the response into dst.
+func execQueryInto[M Model](ctx context.Context, client *Client, q Queryable, root string, dst interface{}) error {
+	data, err := execQuery[M, json.RawMessage](ctx, client, q, root)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
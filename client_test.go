@@ -3,6 +3,7 @@ package eywa_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -176,3 +177,312 @@ func TestRawClient(t *testing.T) {
 		})
 	}
 }
+
+func TestClientAutoParameterize(t *testing.T) {
+	var reqBody struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": {"test_model": []}}`))
+	}))
+	defer server.Close()
+
+	gqlClient := eywa.NewClient(server.URL, &eywa.ClientOpts{AutoParameterize: true})
+	_, err := eywa.Get[testModel]().Where(
+		eywa.Eq[testModel](testModel_NameField("abc")),
+	).Select(testModel_ID).Exec(gqlClient)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	expectedQuery := `query get_test_model($name: String!) {
+test_model(where: {name: {_eq: $name}}) {
+id
+}
+}`
+	if reqBody.Query != expectedQuery {
+		t.Errorf("Expected query %s, got %s", expectedQuery, reqBody.Query)
+	}
+	if reqBody.Variables["name"] != "abc" {
+		t.Errorf("Expected variable name to be abc, got %v", reqBody.Variables["name"])
+	}
+}
+
+func TestUpdateManyExec(t *testing.T) {
+	server := newTestServer(`{"data": {"update_test_model_many": [
+		{"affected_rows": 1, "returning": [{"id": 1, "name": "a"}]},
+		{"affected_rows": 0, "returning": []}
+	]}}`)
+	defer server.Close()
+
+	resp, err := eywa.UpdateMany[testModel](
+		eywa.Update[testModel]().Where(eywa.Eq[testModel](testModel_NameField("x"))).Set(testModel_NameField("a")),
+		eywa.Update[testModel]().Where(eywa.Eq[testModel](testModel_NameField("y"))).Set(testModel_NameField("b")),
+	).Select(testModel_ID, testModel_Name).Exec(eywa.NewClient(server.URL, nil))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(resp) != 2 {
+		t.Errorf("Expected 2 responses, got %d", len(resp))
+		return
+	}
+	if resp[0].AffectedRows != 1 || len(resp[0].Returning) != 1 || resp[0].Returning[0].Name != "a" {
+		t.Errorf("Unexpected first response %+v", resp[0])
+	}
+	if resp[1].AffectedRows != 0 || len(resp[1].Returning) != 0 {
+		t.Errorf("Unexpected second response %+v", resp[1])
+	}
+}
+
+func TestMutationExec(t *testing.T) {
+	var reqBody struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": {
+			"update_test_model_0": {"affected_rows": 1, "returning": [{"id": 1, "name": "a"}]},
+			"insert_test_model_one_1": {"id": 2, "name": "b"},
+			"delete_test_model_2": {"affected_rows": 3, "returning": []}
+		}}`))
+	}))
+	defer server.Close()
+
+	var updated []testModel
+	var inserted *testModel
+	var deleted *eywa.MutationResponse[testModel]
+	err := eywa.Mutation(
+		eywa.Into(eywa.Update[testModel]().Where(
+			eywa.Eq[testModel](testModel_NameField("x")),
+		).Set(
+			testModel_NameField("a"),
+		).Select(testModel_ID), &updated),
+		eywa.Into(eywa.InsertOne(testModel_NameField("b")).Select(testModel_ID), &inserted),
+		eywa.Into(eywa.Delete[testModel]().Select(testModel_ID), &deleted),
+	).Exec(eywa.NewClient(server.URL, &eywa.ClientOpts{AutoParameterize: true}))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	expectedQuery := `mutation atomic($name: String!, $name_1: String!, $name_2: String!) {
+update_test_model_0: update_test_model(where: {name: {_eq: $name}}, _set: {name: $name_1}) {
+returning {
+id
+}
+}
+insert_test_model_one_1: insert_test_model_one(object: {name: $name_2}) {
+id
+}
+delete_test_model_2: delete_test_model(where: {_not: {}}) {
+affected_rows
+returning {
+id
+}
+}
+}`
+	if reqBody.Query != expectedQuery {
+		t.Errorf("Expected query %s, got %s", expectedQuery, reqBody.Query)
+	}
+	if reqBody.Variables["name"] != "x" || reqBody.Variables["name_1"] != "a" || reqBody.Variables["name_2"] != "b" {
+		t.Errorf("Unexpected variables %v", reqBody.Variables)
+	}
+	if len(updated) != 1 || updated[0].Name != "a" {
+		t.Errorf("Unexpected update result %+v", updated)
+	}
+	if inserted == nil || inserted.ID != 2 {
+		t.Errorf("Unexpected insert result %+v", inserted)
+	}
+	if deleted == nil || deleted.AffectedRows != 3 {
+		t.Errorf("Unexpected delete result %+v", deleted)
+	}
+}
+
+func TestBatchExec(t *testing.T) {
+	server := newTestServer(`{"data": {
+		"test_model_0": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}],
+		"test_model_by_pk_1": {"id": 3, "name": "c"},
+		"test_model_aggregate_2": {"aggregate": {"count": 7}}
+	}}`)
+	defer server.Close()
+
+	all := eywa.Fetch(eywa.Get[testModel]().Select(testModel_ID))
+	one := eywa.Fetch(eywa.GetByPk(eywa.Pk(eywa.Field[testModel]{Name: "id", Value: 3})).Select(testModel_ID))
+	count := eywa.Fetch(eywa.Aggregate[testModel]().Select(eywa.Count[testModel]()))
+	err := eywa.Batch(all, one, count).Exec(eywa.NewClient(server.URL, nil))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if rows := all.Value(); len(rows) != 2 || rows[1].Name != "b" {
+		t.Errorf("Unexpected get result %+v", rows)
+	}
+	if row := one.Value(); row == nil || row.ID != 3 {
+		t.Errorf("Unexpected get by pk result %+v", row)
+	}
+	if result := count.Value(); result == nil || result.Aggregate.Count != 7 {
+		t.Errorf("Unexpected aggregate result %+v", result)
+	}
+}
+
+func TestAggregateCounts(t *testing.T) {
+	server := newTestServer(`{"data": {"test_model_aggregate": {"aggregate": {
+		"count": 5,
+		"count_name": 4,
+		"count_distinct_name": 2
+	}}}}`)
+	defer server.Close()
+
+	result, err := eywa.Aggregate[testModel]().Select(
+		eywa.Count[testModel](),
+		eywa.CountColumns(testModel_Name),
+		eywa.CountDistinct(testModel_Name),
+	).Exec(eywa.NewClient(server.URL, nil))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if n := result.CountOf(eywa.Count[testModel]()); n != 5 || result.Aggregate.Count != 5 {
+		t.Errorf("Expected count 5, got %d", n)
+	}
+	if n := result.CountOf(eywa.CountColumns(testModel_Name)); n != 4 {
+		t.Errorf("Expected count of names 4, got %d", n)
+	}
+	if n := result.CountOf(eywa.CountDistinct(testModel_Name)); n != 2 {
+		t.Errorf("Expected count of distinct names 2, got %d", n)
+	}
+}
+
+func TestExecInto(t *testing.T) {
+	server := newTestServer(`{"data": {
+		"test_model": [{"id": 1, "first": "a", "second": "b"}],
+		"first_model": [{"id": 2}]
+	}}`)
+	defer server.Close()
+	client := eywa.NewClient(server.URL, nil)
+
+	q := eywa.Get[testModel]().Select(
+		testModel_ID,
+		eywa.As("first", testModel_Name),
+		eywa.As("second", testModel_Name),
+	)
+	var rows []struct {
+		ID     int    `json:"id"`
+		First  string `json:"first"`
+		Second string `json:"second"`
+	}
+	if err := q.ExecInto(client, &rows); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(rows) != 1 || rows[0].First != "a" || rows[0].Second != "b" {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+
+	var maps []map[string]interface{}
+	if err := q.ExecInto(client, &maps); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(maps) != 1 || maps[0]["second"] != "b" {
+		t.Errorf("Unexpected rows %+v", maps)
+	}
+
+	first := eywa.Fetch(eywa.Get[testModel]().Select(testModel_ID)).As("first_model")
+	if err := eywa.Batch(first).Exec(client); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if rows := first.Value(); len(rows) != 1 || rows[0].ID != 2 {
+		t.Errorf("Unexpected aliased result %+v", rows)
+	}
+}
+
+func TestMutationExecInto(t *testing.T) {
+	server := newTestServer(`{"data": {
+		"insert_test_model_one": {"id": 1, "label": "a"},
+		"update_test_model": {"returning": [{"id": 2, "label": "b"}]},
+		"update_test_model_0": {"returning": [{"id": 3, "label": "c"}]},
+		"labels": [{"label": "d"}]
+	}}`)
+	defer server.Close()
+	client := eywa.NewClient(server.URL, nil)
+
+	type labelled struct {
+		ID    int    `json:"id"`
+		Label string `json:"label"`
+	}
+	var inserted labelled
+	err := eywa.InsertOne(testModel_NameField("a")).Select(testModel_ID, eywa.As("label", testModel_Name)).ExecInto(client, &inserted)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if inserted.ID != 1 || inserted.Label != "a" {
+		t.Errorf("Unexpected insert result %+v", inserted)
+	}
+
+	update := eywa.Update[testModel]().Set(testModel_NameField("b")).Select(testModel_ID, eywa.As("label", testModel_Name))
+	var updated []labelled
+	if err := update.ExecInto(client, &updated); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(updated) != 1 || updated[0].Label != "b" {
+		t.Errorf("Unexpected update result %+v", updated)
+	}
+
+	var updatedInMutation struct {
+		Returning []labelled `json:"returning"`
+	}
+	if err := eywa.Mutation(eywa.Into(update, nil).DecodeInto(&updatedInMutation)).Exec(client); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(updatedInMutation.Returning) != 1 || updatedInMutation.Returning[0].Label != "c" {
+		t.Errorf("Unexpected mutation result %+v", updatedInMutation)
+	}
+
+	var labels []map[string]string
+	fetched := eywa.Fetch(eywa.Get[testModel]().Select(eywa.As("label", testModel_Name))).As("labels").DecodeInto(&labels)
+	if err := eywa.Batch(fetched).Exec(client); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	if len(labels) != 1 || labels[0]["label"] != "d" {
+		t.Errorf("Unexpected batch result %+v", labels)
+	}
+}
+
+func TestInvalidAliases(t *testing.T) {
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected %s to panic", name)
+			}
+		}()
+		f()
+	}
+	get := eywa.Get[testModel]().Select(testModel_ID)
+
+	expectPanic("invalid field alias", func() { eywa.As("first name", testModel_Name) })
+	expectPanic("invalid batch alias", func() { eywa.Fetch(get).As("all-models") })
+	expectPanic("duplicate batch alias", func() {
+		eywa.Batch(eywa.Fetch(get).As("models"), eywa.Fetch(get).As("models"))
+	})
+	expectPanic("alias of another field", func() {
+		eywa.Batch(eywa.Fetch(get), eywa.Fetch(get).As("test_model_0"))
+	})
+	expectPanic("duplicate mutation alias", func() {
+		insert := eywa.InsertOne(testModel_NameField("a")).Select(testModel_ID)
+		eywa.Mutation(eywa.Into(insert, nil).As("one"), eywa.Into(insert, nil).As("one"))
+	})
+}
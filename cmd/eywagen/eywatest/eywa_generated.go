@@ -5,7 +5,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/imperfect-fourth/eywa"
 	"fmt"
-	"bytes"
 	"time"
 )
 
@@ -17,6 +16,7 @@ func testTable_NameField(val string) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "name",
 		Value: val,
+		Type: "String",
 	}
 }
 
@@ -26,12 +26,29 @@ func testTable_NameVar(val string) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_Name", eywa.StringVar[string](val)),
 	}
 }
+
+func testTable_NameIn(vals ...string) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "name",
+		Value: vals,
+		Type: "String",
+	})
+}
+
+func testTable_NameNin(vals ...string) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "name",
+		Value: vals,
+		Type: "String",
+	})
+}
 const testTable_Age eywa.FieldName[testTable] = "age"
 
 func testTable_AgeField(val *int) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "age",
 		Value: val,
+		Type: "Int",
 	}
 }
 
@@ -41,12 +58,29 @@ func testTable_AgeVar(val *int) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_Age", eywa.NullableIntVar[*int](val)),
 	}
 }
+
+func testTable_AgeIn(vals ...int) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "age",
+		Value: vals,
+		Type: "Int",
+	})
+}
+
+func testTable_AgeNin(vals ...int) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "age",
+		Value: vals,
+		Type: "Int",
+	})
+}
 const testTable_ID eywa.FieldName[testTable] = "id"
 
 func testTable_IDField(val int) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "id",
 		Value: val,
+		Type: "Int",
 	}
 }
 
@@ -56,12 +90,29 @@ func testTable_IDVar(val int) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_ID", eywa.IntVar[int](val)),
 	}
 }
+
+func testTable_IDIn(vals ...int) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "id",
+		Value: vals,
+		Type: "Int",
+	})
+}
+
+func testTable_IDNin(vals ...int) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "id",
+		Value: vals,
+		Type: "Int",
+	})
+}
 const testTable_IDd eywa.FieldName[testTable] = "idd"
 
 func testTable_IDdField(val int32) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "idd",
 		Value: val,
+		Type: "Int",
 	}
 }
 
@@ -71,6 +122,22 @@ func testTable_IDdVar(val int32) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_IDd", eywa.IntVar[int32](val)),
 	}
 }
+
+func testTable_IDdIn(vals ...int32) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "idd",
+		Value: vals,
+		Type: "Int",
+	})
+}
+
+func testTable_IDdNin(vals ...int32) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "idd",
+		Value: vals,
+		Type: "Int",
+	})
+}
 const testTable_custom eywa.FieldName[testTable] = "custom"
 
 func testTable_customField(val *customType) eywa.Field[testTable] {
@@ -95,15 +162,46 @@ func testTable_customArrField(val []*customType) eywa.Field[testTable] {
 	}
 }
 
-func testTable_testTable2(subField eywa.FieldName[testTable2], subFields ...eywa.FieldName[testTable2]) eywa.FieldName[testTable] {
-	buf := bytes.NewBuffer([]byte("testTable2 {\n"))
-	buf.WriteString(string(subField))
-	for _, f := range subFields {
-		buf.WriteString("\n")
-		buf.WriteString(string(f))
+func testTable_testTable2(subField eywa.Selection[testTable2], subFields ...eywa.Selection[testTable2]) eywa.Selection[testTable] {
+	return eywa.Relationship[testTable, testTable2]("testTable2", eywa.RelArgs[testTable2](), subField, subFields...)
+}
+
+func testTable_testTable2Insert(data eywa.NestedObjectInsert[testTable2]) eywa.Field[testTable] {
+	return eywa.Field[testTable]{
+		Name:  "testTable2",
+		Value: data,
 	}
-	buf.WriteString("\n}")
-	return eywa.FieldName[testTable](buf.String())
+}
+
+func testTable_testTable2Where(w *eywa.WhereExpr) *eywa.WhereExpr {
+	return eywa.Rel[testTable, testTable2]("testTable2", w)
+}
+
+func testTable_testTable2OrderBy(ob eywa.OrderByExpr) eywa.OrderByExpr {
+	return eywa.OrderByRel[testTable, testTable2]("testTable2", ob)
+}
+
+func testTable_testTable2s(subField eywa.Selection[testTable2], subFields ...eywa.Selection[testTable2]) eywa.Selection[testTable] {
+	return eywa.Relationship[testTable, testTable2]("testTable2s", eywa.RelArgs[testTable2](), subField, subFields...)
+}
+
+func testTable_testTable2sWith(args eywa.RelationshipArgs[testTable2], subField eywa.Selection[testTable2], subFields ...eywa.Selection[testTable2]) eywa.Selection[testTable] {
+	return eywa.Relationship[testTable, testTable2]("testTable2s", args, subField, subFields...)
+}
+
+func testTable_testTable2sInsert(data eywa.NestedArrayInsert[testTable2]) eywa.Field[testTable] {
+	return eywa.Field[testTable]{
+		Name:  "testTable2s",
+		Value: data,
+	}
+}
+
+func testTable_testTable2sWhere(w *eywa.WhereExpr) *eywa.WhereExpr {
+	return eywa.Rel[testTable, testTable2]("testTable2s", w)
+}
+
+func testTable_testTable2sAggregateOrderBy(ob eywa.OrderByExpr) eywa.OrderByExpr {
+	return eywa.OrderByAggregate[testTable, testTable2]("testTable2s", ob)
 }
 const testTable_JsonBCol eywa.FieldName[testTable] = "jsonb_col"
 
@@ -111,6 +209,7 @@ func testTable_JsonBColField(val jsonbcol) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "jsonb_col",
 		Value: val,
+		Type: "jsonb",
 	}
 }
 
@@ -126,6 +225,7 @@ func testTable_StatusField(val eywa.Enum[status]) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "status",
 		Value: val,
+		Type: "status_enum",
 	}
 }
 
@@ -135,6 +235,22 @@ func testTable_StatusVar(val eywa.Enum[status]) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_Status", eywa.StringVar[eywa.Enum[status]](val)),
 	}
 }
+
+func testTable_StatusIn(vals ...eywa.Enum[status]) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "status",
+		Value: vals,
+		Type: "status_enum",
+	})
+}
+
+func testTable_StatusNin(vals ...eywa.Enum[status]) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "status",
+		Value: vals,
+		Type: "status_enum",
+	})
+}
 const testTable_Generic eywa.FieldName[testTable] = "generic_type"
 
 func testTable_GenericField(val GenericType[string, int]) eywa.Field[testTable] {
@@ -150,12 +266,27 @@ func testTable_GenericVar(val GenericType[string, int]) eywa.Field[testTable] {
 		Value: eywa.QueryVar("testTable_Generic", eywa.StringVar[GenericType[string, int]](val)),
 	}
 }
+
+func testTable_GenericIn(vals ...GenericType[string, int]) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable]{
+		Name: "generic_type",
+		Value: vals,
+	})
+}
+
+func testTable_GenericNin(vals ...GenericType[string, int]) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable]{
+		Name: "generic_type",
+		Value: vals,
+	})
+}
 const testTable_ArrayCol eywa.FieldName[testTable] = "testarr"
 
 func testTable_ArrayColField(val []string) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "testarr",
 		Value: val,
+		Type: "_text",
 	}
 }
 const testTable_timestamp eywa.FieldName[testTable] = "timestamp"
@@ -164,6 +295,7 @@ func testTable_timestampField(val time.Time) eywa.Field[testTable] {
 	return eywa.Field[testTable]{
 		Name: "timestamp",
 		Value: val,
+		Type: "timestamptz",
 	}
 }
 
@@ -174,6 +306,12 @@ func testTable_timestampVar[T interface{eywa.JSONValue | eywa.JSONBValue;eywa.Ty
 	}
 }
 
+func testTable_ByPk(id int) eywa.PrimaryKey[testTable] {
+	return eywa.PrimaryKey[testTable]{
+		testTable_IDField(id),
+	}
+}
+
 var testTable2_PkeyConstraint = eywa.Constraint[testTable2](fmt.Sprintf("%s_pkey", (new(testTable2)).TableName()))
 const testTable2_ID eywa.FieldName[testTable2] = "id"
 
@@ -181,6 +319,7 @@ func testTable2_IDField(val uuid.UUID) eywa.Field[testTable2] {
 	return eywa.Field[testTable2]{
 		Name: "id",
 		Value: val,
+		Type: "uuid",
 	}
 }
 const testTable2_Age eywa.FieldName[testTable2] = "age"
@@ -189,6 +328,7 @@ func testTable2_AgeField(val int) eywa.Field[testTable2] {
 	return eywa.Field[testTable2]{
 		Name: "age",
 		Value: val,
+		Type: "Int",
 	}
 }
 
@@ -198,3 +338,25 @@ func testTable2_AgeVar(val int) eywa.Field[testTable2] {
 		Value: eywa.QueryVar("testTable2_Age", eywa.IntVar[int](val)),
 	}
 }
+
+func testTable2_AgeIn(vals ...int) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[testTable2]{
+		Name: "age",
+		Value: vals,
+		Type: "Int",
+	})
+}
+
+func testTable2_AgeNin(vals ...int) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[testTable2]{
+		Name: "age",
+		Value: vals,
+		Type: "Int",
+	})
+}
+
+func testTable2_ByPk(id uuid.UUID) eywa.PrimaryKey[testTable2] {
+	return eywa.PrimaryKey[testTable2]{
+		testTable2_IDField(id),
+	}
+}
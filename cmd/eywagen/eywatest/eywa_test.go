@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/imperfect-fourth/eywa"
@@ -219,3 +220,1167 @@ id
 		assert.Equal(t, &testTable2{ID: id, Age: 20}, resp)
 	}
 }
+
+func TestDeleteQuery(t *testing.T) {
+	q := eywa.Delete[testTable]().Where(
+		eywa.Eq[testTable](testTable_IDField(3)),
+	).Select(
+		testTable_Name,
+		testTable_ID,
+	)
+
+	expected := `mutation delete_test_table {
+delete_test_table(where: {id: {_eq: 3}}) {
+affected_rows
+returning {
+id
+name
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestDeleteQueryWithoutWhere(t *testing.T) {
+	q := eywa.Delete[testTable]().Select(testTable_ID)
+
+	expected := `mutation delete_test_table {
+delete_test_table(where: {_not: {}}) {
+affected_rows
+returning {
+id
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestDeleteByPkQuery(t *testing.T) {
+	id := uuid.New()
+	q := eywa.DeleteByPk(
+		testTable2_ByPk(id),
+	).Select(
+		testTable2_ID,
+		testTable2_Age,
+	)
+
+	expected := fmt.Sprintf(`mutation delete_testTable2_by_pk {
+delete_testTable2_by_pk(id: "%s") {
+age
+id
+}
+}`, id.String())
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestInsertQuery(t *testing.T) {
+	id1, id2 := uuid.New(), uuid.New()
+	q := eywa.Insert(
+		eywa.Row(testTable2_IDField(id1), testTable2_AgeField(10)),
+		eywa.Row(testTable2_IDField(id2), testTable2_AgeField(20)),
+	).OnConflict(
+		testTable2_PkeyConstraint,
+		testTable2_Age,
+	).Select(
+		testTable2_ID,
+	)
+
+	expected := fmt.Sprintf(`mutation insert_testTable2 {
+insert_testTable2(objects: [{age: 10, id: "%s"}, {age: 20, id: "%s"}], on_conflict: {constraint: testTable2_pkey, update_columns: [age]}) {
+affected_rows
+returning {
+id
+}
+}
+}`, id1.String(), id2.String())
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestGetByPkQuery(t *testing.T) {
+	q := eywa.GetByPk(testTable_ByPk(3)).Select(testTable_Name)
+
+	expected := `query get_test_table_by_pk {
+test_table_by_pk(id: 3) {
+name
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestUpdateByPkQuery(t *testing.T) {
+	q := eywa.UpdateByPk(testTable_ByPk(3)).Set(
+		testTable_NameVar("updatetest"),
+	).Select(
+		testTable_ID,
+		testTable_Name,
+	)
+
+	expected := `mutation update_test_table_by_pk($testTable_Name: String!) {
+update_test_table_by_pk(_set: {name: $testTable_Name}, pk_columns: {id: 3}) {
+name
+id
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name": "updatetest",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestAggregateQuery(t *testing.T) {
+	q := eywa.Aggregate[testTable]().Where(
+		eywa.Gt[testTable](testTable_IDField(3)),
+	).Limit(5).Select(
+		eywa.Count[testTable](),
+		eywa.CountDistinct(testTable_Name),
+		eywa.Sum(testTable_Age, testTable_ID),
+		eywa.Avg(testTable_Age),
+		eywa.Max(testTable_Age),
+	).Nodes(
+		testTable_Name,
+	)
+
+	expected := `query aggregate_test_table {
+test_table_aggregate(limit: 5, where: {id: {_gt: 3}}) {
+aggregate {
+count_distinct_name: count(columns: [name], distinct: true)
+sum {
+id
+age
+}
+avg {
+age
+}
+max {
+age
+}
+count
+}
+nodes {
+name
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestSelectQueryVariables(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.And(
+			eywa.Eq[testTable](testTable_NameVar("abcd")),
+			eywa.Gt[testTable](testTable_IDField(3)),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($testTable_Name: String!) {
+test_table(where: {_and: [{name: {_eq: $testTable_Name}}, {id: {_gt: 3}}]}) {
+name
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name": "abcd",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestInsertOneQueryVariables(t *testing.T) {
+	q := eywa.InsertOne(
+		testTable2_AgeVar(10),
+	).Select(
+		testTable2_ID,
+	)
+
+	expected := `mutation insert_testTable2_one($testTable2_Age: Int!) {
+insert_testTable2_one(object: {age: $testTable2_Age}) {
+id
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable2_Age": 10,
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestUpdateQueryWhereVariables(t *testing.T) {
+	q := eywa.Update[testTable]().Where(
+		eywa.Eq[testTable](testTable_IDVar(3)),
+	).Set(
+		testTable_NameVar("updatetest"),
+	).Select(
+		testTable_ID,
+	)
+
+	expected := `mutation update_test_table($testTable_ID: Int!, $testTable_Name: String!) {
+update_test_table(where: {id: {_eq: $testTable_ID}}, _set: {name: $testTable_Name}) {
+returning {
+id
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_ID":   3,
+		"testTable_Name": "updatetest",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+func TestAggregateNodesQueryVariables(t *testing.T) {
+	q := eywa.Aggregate[testTable]().Where(
+		eywa.Eq[testTable](testTable_NameVar("abcd")),
+	).Select(
+		eywa.Count[testTable](),
+	).Nodes(
+		testTable_ID,
+	)
+
+	expected := `query aggregate_test_table($testTable_Name: String!) {
+test_table_aggregate(where: {name: {_eq: $testTable_Name}}) {
+aggregate {
+count
+}
+nodes {
+id
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name": "abcd",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+
+func TestQueryVariableNaming(t *testing.T) {
+	q := eywa.Update[testTable]().Where(
+		eywa.Or(
+			eywa.Eq[testTable](testTable_NameVar("abc")),
+			eywa.Eq[testTable](testTable_NameVar("abcd")),
+			eywa.Neq[testTable](testTable_NameVar("abc")),
+		),
+	).Set(
+		testTable_NameVar("updatetest"),
+	).Select(
+		testTable_ID,
+	)
+
+	expected := `mutation update_test_table($testTable_Name: String!, $testTable_Name_1: String!, $testTable_Name_2: String!) {
+update_test_table(where: {_or: [{name: {_eq: $testTable_Name}}, {name: {_eq: $testTable_Name_1}}, {name: {_neq: $testTable_Name}}]}, _set: {name: $testTable_Name_2}) {
+returning {
+id
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name":   "abc",
+		"testTable_Name_1": "abcd",
+		"testTable_Name_2": "updatetest",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestParameterizedQuery(t *testing.T) {
+	age := 10
+	id := uuid.New()
+	q := eywa.Get[testTable]().Limit(2).OrderBy(
+		eywa.Desc[testTable](testTable_Name),
+	).Where(
+		eywa.Or(
+			eywa.Eq[testTable](testTable_NameField("abcd")),
+			eywa.Eq[testTable](testTable_AgeField(&age)),
+			eywa.Eq[testTable](testTable_StatusField(state1)),
+			eywa.Eq[testTable](testTable_NameVar("abc")),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($limit: Int!, $name: String!, $age: Int, $status: status_enum!, $testTable_Name: String!, $name_order: order_by!) {
+test_table(limit: $limit, where: {_or: [{name: {_eq: $name}}, {age: {_eq: $age}}, {status: {_eq: $status}}, {name: {_eq: $testTable_Name}}]}, order_by: {name: $name_order}) {
+name
+}
+}`
+	expectedVars := map[string]interface{}{
+		"limit":          2,
+		"name":           "abcd",
+		"age":            &age,
+		"status":         state1,
+		"testTable_Name": "abc",
+		"name_order":     "desc",
+	}
+	pq := eywa.Parameterize(q)
+	assert.Equal(t, expected, pq.Query())
+	assert.Equal(t, expectedVars, pq.Variables())
+
+	iq := eywa.Parameterize(eywa.InsertOne(
+		testTable2_IDField(id),
+		testTable2_AgeField(10),
+	).Select(testTable2_ID))
+
+	expected = `mutation insert_testTable2_one($age: Int!, $id: uuid!) {
+insert_testTable2_one(object: {age: $age, id: $id}) {
+id
+}
+}`
+	expectedVars = map[string]interface{}{
+		"age": 10,
+		"id":  id,
+	}
+	assert.Equal(t, expected, iq.Query())
+	assert.Equal(t, expectedVars, iq.Variables())
+
+	// the query text doesn't depend on whether the values are equal
+	same := eywa.Parameterize(eywa.Get[testTable]().Where(eywa.Or(
+		eywa.Eq[testTable](testTable_NameField("a")),
+		eywa.Eq[testTable](testTable_NameField("a")),
+	)).Select(testTable_Name))
+	different := eywa.Parameterize(eywa.Get[testTable]().Where(eywa.Or(
+		eywa.Eq[testTable](testTable_NameField("a")),
+		eywa.Eq[testTable](testTable_NameField("b")),
+	)).Select(testTable_Name))
+
+	expected = `query get_test_table($name: String!, $name_1: String!) {
+test_table(where: {_or: [{name: {_eq: $name}}, {name: {_eq: $name_1}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, same.Query())
+	assert.Equal(t, expected, different.Query())
+	assert.Equal(t, map[string]interface{}{"name": "a", "name_1": "a"}, same.Variables())
+
+	// the types of the variables come from the schema, and values of fields
+	// without one can't be lifted
+	jsonb := jsonbcol{StrField: "a"}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	typed := eywa.Parameterize(eywa.Get[testTable]().Where(eywa.And(
+		eywa.Eq[testTable](testTable_JsonBColField(jsonb)),
+		eywa.Gt[testTable](eywa.Field[testTable]{Name: "idd", Value: int64(5), Type: "bigint"}),
+		eywa.Lt[testTable](testTable_timestampField(ts)),
+		eywa.Eq[testTable](testTable_ArrayColField([]string{"a"})),
+		testTable_StatusIn(state1),
+	)).Select(testTable_Name))
+
+	expected = `query get_test_table($jsonb_col: jsonb!, $idd: bigint!, $timestamp: timestamptz!, $testarr: _text!, $status: [status_enum!]!) {
+test_table(where: {_and: [{jsonb_col: {_eq: $jsonb_col}}, {idd: {_gt: $idd}}, {timestamp: {_lt: $timestamp}}, {testarr: {_eq: $testarr}}, {status: {_in: $status}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, typed.Query())
+	assert.Equal(t, map[string]interface{}{
+		"jsonb_col": jsonb,
+		"idd":       int64(5),
+		"timestamp": ts,
+		"testarr":   []string{"a"},
+		"status":    []eywa.Enum[status]{state1},
+	}, typed.Variables())
+
+	untyped := eywa.Parameterize(eywa.Get[testTable]().Where(
+		eywa.Lt[testTable](eywa.Field[testTable]{Name: "idd", Value: int64(9)}),
+	).Select(testTable_Name))
+	assert.PanicsWithValue(t, "eywa: can't parameterize the value of field idd, which has no Type", func() { untyped.Query() })
+}
+
+func TestWhereOperators(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.And(
+			testTable_IDIn(1, 2, 3),
+			testTable_StatusNin(state1),
+			eywa.IsNull(testTable_Age, false),
+			eywa.Ilike[testTable](testTable_NameField("%ab%")),
+			eywa.Nregex[testTable](testTable_NameField("^x")),
+			eywa.In[testTable](eywa.Field[testTable]{
+				Name:  "idd",
+				Value: eywa.QueryVar("idds", eywa.ListVar([]int32{4, 5}, eywa.IntVar[int32])),
+			}),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($idds: [Int!]!) {
+test_table(where: {_and: [{id: {_in: [1,2,3]}}, {status: {_nin: [state1]}}, {age: {_is_null: false}}, {name: {_ilike: "%ab%"}}, {name: {_nregex: "^x"}}, {idd: {_in: $idds}}]}) {
+name
+}
+}`
+	expectedVars := map[string]interface{}{
+		"idds": []int32{4, 5},
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+
+	expected = `query get_test_table($id: [Int!]!, $status: [status_enum!]!, $age: Boolean!, $name: String!, $name_1: String!, $idds: [Int!]!) {
+test_table(where: {_and: [{id: {_in: $id}}, {status: {_nin: $status}}, {age: {_is_null: $age}}, {name: {_ilike: $name}}, {name: {_nregex: $name_1}}, {idd: {_in: $idds}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, eywa.Parameterize(q).Query())
+}
+
+func TestEmptyListOperators(t *testing.T) {
+	var ids []int
+	q := eywa.Get[testTable]().Where(
+		eywa.And(
+			testTable_IDIn(ids...),
+			testTable_StatusNin(),
+			eywa.In[testTable](eywa.Field[testTable]{
+				Name:  "idd",
+				Value: eywa.QueryVar("idds", eywa.ListVar([]int32(nil), eywa.IntVar[int32])),
+			}),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($idds: [Int!]!) {
+test_table(where: {_and: [{id: {_in: []}}, {status: {_nin: []}}, {idd: {_in: $idds}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, map[string]interface{}{"idds": []int32{}}, q.Variables())
+
+	pq := eywa.Parameterize(q)
+	expected = `query get_test_table($id: [Int!]!, $status: [status_enum!]!, $idds: [Int!]!) {
+test_table(where: {_and: [{id: {_in: $id}}, {status: {_nin: $status}}, {idd: {_in: $idds}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, pq.Query())
+	assert.Equal(t, map[string]interface{}{"id": []int{}, "status": []eywa.Enum[status]{}, "idds": []int32{}}, pq.Variables())
+
+	// outside of the list operators, a nil list is null, eg. to clear a column
+	uq := eywa.Update[testTable]().Where(
+		eywa.Eq[testTable](testTable_IDField(1)),
+	).Set(
+		testTable_ArrayColField(nil),
+	).Select(testTable_Name)
+	expected = `mutation update_test_table {
+update_test_table(where: {id: {_eq: 1}}, _set: {testarr: null}) {
+returning {
+name
+}
+}
+}`
+	assert.Equal(t, expected, uq.Query())
+
+	puq := eywa.Parameterize(eywa.Update[testTable]().Where(
+		eywa.Eq[testTable](testTable_IDField(1)),
+	).Set(
+		eywa.Field[testTable]{Name: "testarr", Value: []string(nil), Type: "[String!]"},
+	).Select(testTable_Name))
+	assert.Contains(t, puq.Query(), "$testarr: [String!])")
+	assert.Equal(t, map[string]interface{}{"id": 1, "testarr": []string(nil)}, puq.Variables())
+}
+
+func TestJSONBOperators(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.And(
+			eywa.Contains[testTable](testTable_JsonBColField(jsonbcol{StrField: "a", ArrField: []int{1}})),
+			eywa.ContainedIn[testTable](eywa.Field[testTable]{
+				Name:  "jsonb_col",
+				Value: map[string]interface{}{"foo-bar": 1},
+			}),
+			eywa.HasKey(testTable_JsonBCol, "str_field"),
+			eywa.HasKeysAny(testTable_JsonBCol, "int_field", "bool_field"),
+			eywa.CastString(eywa.Ilike[testTable](eywa.Field[testTable]{
+				Name:  "jsonb_col",
+				Value: "%abc%",
+			})),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($jsonb_col: jsonb) {
+test_table(where: {_and: [{jsonb_col: {_contains: {arr_field: [1], bool_field: false, int_field: 0, str_field: "a"}}}, {jsonb_col: {_contained_in: $jsonb_col}}, {jsonb_col: {_has_key: "str_field"}}, {jsonb_col: {_has_keys_any: ["int_field","bool_field"]}}, {jsonb_col: {_cast: {String: {_ilike: "%abc%"}}}}]}) {
+name
+}
+}`
+	expectedVars := map[string]interface{}{
+		"jsonb_col": map[string]interface{}{"foo-bar": 1},
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestCastString(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.And(
+			eywa.CastString(eywa.Ilike[testTable](eywa.Field[testTable]{
+				Name:  "jsonb_col",
+				Value: "%abc%",
+			})),
+			eywa.In[testTable](testTable_IDField(1)),
+			testTable_testTable2Where(eywa.CastString(eywa.Like[testTable2](eywa.Field[testTable2]{
+				Name:  "metadata",
+				Value: "a%",
+			}))),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table {
+test_table(where: {_and: [{jsonb_col: {_cast: {String: {_ilike: "%abc%"}}}}, {id: {_in: 1}}, {testTable2: {metadata: {_cast: {String: {_like: "a%"}}}}}]}) {
+name
+}
+}`
+	assert.Equal(t, expected, q.Query())
+
+	expected = `query get_test_table($jsonb_col: String!, $id: [Int!]!, $metadata: String!) {
+test_table(where: {_and: [{jsonb_col: {_cast: {String: {_ilike: $jsonb_col}}}}, {id: {_in: $id}}, {testTable2: {metadata: {_cast: {String: {_like: $metadata}}}}}]}) {
+name
+}
+}`
+	pq := eywa.Parameterize(q)
+	assert.Equal(t, expected, pq.Query())
+	assert.Equal(t, map[string]interface{}{"jsonb_col": "%abc%", "id": 1, "metadata": "a%"}, pq.Variables())
+
+	// a cast list comparison compares with a list of the cast type
+	cast := eywa.Parameterize(eywa.Get[testTable]().Where(
+		eywa.CastString(eywa.In[testTable](eywa.Field[testTable]{
+			Name:  "jsonb_col",
+			Value: []string{"a", "b"},
+		})),
+	).Select(testTable_Name))
+	assert.Contains(t, cast.Query(), "$jsonb_col: [String!]!")
+
+	// the values of cast jsonb comparisons are still lifted as jsonb
+	jsonb := eywa.Parameterize(eywa.Get[testTable]().Where(
+		eywa.CastString(eywa.Contains[testTable](eywa.Field[testTable]{
+			Name:  "jsonb_col",
+			Value: map[string]interface{}{"a": 1},
+		})),
+	).Select(testTable_Name))
+	assert.Contains(t, jsonb.Query(), "$jsonb_col: jsonb")
+
+	assert.Panics(t, func() {
+		eywa.CastString(eywa.And(eywa.Ilike[testTable](eywa.Field[testTable]{Name: "jsonb_col", Value: "a"})))
+	})
+}
+
+func TestRelationshipWhere(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.Or(
+			testTable_testTable2Where(eywa.Gt[testTable2](testTable2_AgeVar(100))),
+			eywa.Not(testTable_testTable2Where(nil)),
+		),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table($testTable2_Age: Int!) {
+test_table(where: {_or: [{testTable2: {age: {_gt: $testTable2_Age}}}, {_not: {testTable2: {}}}]}) {
+name
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable2_Age": 100,
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestRelationshipSelectArgs(t *testing.T) {
+	q := eywa.Get[testTable]().Select(
+		testTable_testTable2sWith(
+			eywa.RelArgs[testTable2]().
+				Where(eywa.Gt[testTable2](testTable2_AgeField(18))).
+				OrderBy(eywa.Desc(testTable2_Age)).
+				Limit(5),
+			testTable2_ID,
+		),
+		testTable_Name,
+	)
+
+	expected := `query get_test_table {
+test_table {
+name
+testTable2s(limit: 5, where: {age: {_gt: 18}}, order_by: {age: desc}) {
+id
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, testTable_testTable2s(testTable2_ID), testTable_testTable2sWith(eywa.RelArgs[testTable2](), testTable2_ID))
+}
+
+func TestRelationshipSelectArgsVariables(t *testing.T) {
+	q := eywa.Get[testTable]().Where(
+		eywa.Eq[testTable](testTable_NameVar("abc")),
+	).Select(
+		testTable_testTable2sWith(
+			eywa.RelArgs[testTable2]().
+				Where(eywa.Gt[testTable2](testTable2_AgeVar(18))).
+				Limit(5),
+			testTable2_ID,
+		),
+	)
+
+	expected := `query get_test_table($testTable_Name: String!, $testTable2_Age: Int!) {
+test_table(where: {name: {_eq: $testTable_Name}}) {
+testTable2s(limit: 5, where: {age: {_gt: $testTable2_Age}}) {
+id
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name": "abc",
+		"testTable2_Age": 18,
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+
+	pq := eywa.Parameterize(eywa.Get[testTable]().Select(
+		testTable_testTable2sWith(
+			eywa.RelArgs[testTable2]().Where(eywa.Gt[testTable2](testTable2_AgeField(18))),
+			testTable2_ID,
+		),
+	))
+	expected = `query get_test_table($age: Int!) {
+test_table {
+testTable2s(where: {age: {_gt: $age}}) {
+id
+}
+}
+}`
+	assert.Equal(t, expected, pq.Query())
+	assert.Equal(t, map[string]interface{}{"age": 18}, pq.Variables())
+}
+
+func TestNestedInsertQuery(t *testing.T) {
+	q := eywa.InsertOne[testTable](
+		testTable_testTable2Insert(
+			eywa.ObjectInsert(eywa.Row(testTable2_AgeVar(10))).
+				OnConflict(testTable2_PkeyConstraint, testTable2_Age),
+		),
+		testTable_NameField("x"),
+	).Select(testTable_ID)
+
+	expected := `mutation insert_test_table_one($testTable2_Age: Int!) {
+insert_test_table_one(object: {name: "x", testTable2: {data: {age: $testTable2_Age}, on_conflict: {constraint: testTable2_pkey, update_columns: [age]}}}) {
+id
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable2_Age": 10,
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+
+	rows := eywa.ArrayInsert(
+		eywa.Row(testTable2_AgeField(1)),
+		eywa.Row(testTable2_AgeField(2)),
+	)
+	assert.Equal(
+		t,
+		`insert_test_table_one(object: {testTable2s: {data: [{age: 1}, {age: 2}]}}) {
+id
+}`,
+		eywa.InsertOne[testTable](testTable_testTable2sInsert(rows)).Select(testTable_ID).MarshalGQL(),
+	)
+
+	// zero values insert no rows through array relationships, and a row with
+	// default values through object relationships
+	assert.Equal(
+		t,
+		`insert_test_table_one(object: {testTable2s: {data: []}, testTable2: {data: {}}}) {
+id
+}`,
+		eywa.InsertOne[testTable](
+			testTable_testTable2Insert(eywa.NestedObjectInsert[testTable2]{}),
+			testTable_testTable2sInsert(eywa.NestedArrayInsert[testTable2]{}),
+		).Select(testTable_ID).MarshalGQL(),
+	)
+}
+
+func TestUpdateOperators(t *testing.T) {
+	q := eywa.Update[testTable]().Where(
+		eywa.Eq[testTable](testTable_IDField(3)),
+	).Inc(
+		testTable_IDdField(1),
+	).Append(
+		testTable_JsonBColField(jsonbcol{StrField: "a"}),
+	).DeleteKey(
+		testTable_JsonBCol, "str_field",
+	).DeleteElem(
+		testTable_JsonBCol, -1,
+	).DeleteAtPath(
+		testTable_JsonBCol, "arr_field", "0",
+	).Select(
+		testTable_ID,
+	)
+
+	expected := `mutation update_test_table {
+update_test_table(where: {id: {_eq: 3}}, _inc: {idd: 1}, _append: {jsonb_col: {bool_field: false, int_field: 0, str_field: "a"}}, _delete_key: {jsonb_col: "str_field"}, _delete_elem: {jsonb_col: -1}, _delete_at_path: {jsonb_col: ["arr_field","0"]}) {
+returning {
+id
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+
+	expected = `mutation update_test_table($id: Int!, $idd: Int!, $jsonb_col: jsonb, $jsonb_col_1: String!, $jsonb_col_2: Int!, $jsonb_col_3: [String!]!) {
+update_test_table(where: {id: {_eq: $id}}, _inc: {idd: $idd}, _append: {jsonb_col: $jsonb_col}, _delete_key: {jsonb_col: $jsonb_col_1}, _delete_elem: {jsonb_col: $jsonb_col_2}, _delete_at_path: {jsonb_col: $jsonb_col_3}) {
+returning {
+id
+}
+}
+}`
+	assert.Equal(t, expected, eywa.Parameterize(q).Query())
+}
+
+func TestUpdateOperatorsRepeated(t *testing.T) {
+	q := eywa.Update[testTable]().Inc(
+		testTable_IDdField(1),
+		testTable_IDdField(2),
+	).DeleteKey(
+		testTable_JsonBCol, "str_field",
+	).DeleteKey(
+		testTable_JsonBCol, "int_field",
+	).Select(
+		testTable_ID,
+	)
+
+	expected := `mutation update_test_table {
+update_test_table(where: {_not: {}}, _inc: {idd: 2}, _delete_key: {jsonb_col: "int_field"}) {
+returning {
+id
+}
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+func TestUpdateByPkOperators(t *testing.T) {
+	q := eywa.UpdateByPk(testTable_ByPk(3)).Inc(
+		testTable_IDdField(1),
+	).Prepend(
+		testTable_JsonBColField(jsonbcol{StrField: "a"}),
+	).DeleteKey(
+		testTable_JsonBCol, "str_field",
+	).DeleteElem(
+		testTable_JsonBCol, 0,
+	).DeleteAtPath(
+		testTable_JsonBCol, "arr_field", "0",
+	).Select(
+		testTable_ID,
+	)
+
+	expected := `mutation update_test_table_by_pk {
+update_test_table_by_pk(_inc: {idd: 1}, _prepend: {jsonb_col: {bool_field: false, int_field: 0, str_field: "a"}}, _delete_key: {jsonb_col: "str_field"}, _delete_elem: {jsonb_col: 0}, _delete_at_path: {jsonb_col: ["arr_field","0"]}, pk_columns: {id: 3}) {
+id
+}
+}`
+	assert.Equal(t, expected, q.Query())
+
+	appended := eywa.UpdateByPk(testTable_ByPk(3)).Append(
+		testTable_JsonBColField(jsonbcol{StrField: "a"}),
+	).Select(
+		testTable_ID,
+	)
+	expected = `mutation update_test_table_by_pk {
+update_test_table_by_pk(_append: {jsonb_col: {bool_field: false, int_field: 0, str_field: "a"}}, pk_columns: {id: 3}) {
+id
+}
+}`
+	assert.Equal(t, expected, appended.Query())
+}
+
+func TestUpdateManyQuery(t *testing.T) {
+	q := eywa.UpdateMany[testTable](
+		eywa.Update[testTable]().Where(
+			eywa.Eq[testTable](testTable_IDVar(1)),
+		).Set(
+			testTable_NameField("a"),
+		),
+		eywa.Update[testTable]().Where(
+			eywa.Eq[testTable](testTable_IDVar(2)),
+		).Inc(
+			testTable_IDdField(1),
+		),
+		eywa.Update[testTable]().Set(
+			testTable_NameField("c"),
+		),
+	).Select(testTable_ID)
+
+	expected := `mutation update_test_table_many($testTable_ID: Int!, $testTable_ID_1: Int!) {
+update_test_table_many(updates: [{where: {id: {_eq: $testTable_ID}}, _set: {name: "a"}}, {where: {id: {_eq: $testTable_ID_1}}, _inc: {idd: 1}}, {where: {_not: {}}, _set: {name: "c"}}]) {
+affected_rows
+returning {
+id
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_ID":   1,
+		"testTable_ID_1": 2,
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestInsertOnConflictWhereQuery(t *testing.T) {
+	q := eywa.InsertOne(
+		testTable2_AgeField(20),
+	).OnConflictWhere(
+		testTable2_PkeyConstraint,
+		eywa.Lt[testTable2](testTable2_AgeVar(20)),
+		testTable2_Age,
+	).Select(
+		testTable2_ID,
+	)
+	expected := `mutation insert_testTable2_one($testTable2_Age: Int!) {
+insert_testTable2_one(object: {age: 20}, on_conflict: {constraint: testTable2_pkey, update_columns: [age], where: {age: {_lt: $testTable2_Age}}}) {
+id
+}
+}`
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, map[string]interface{}{"testTable2_Age": 20}, q.Variables())
+
+	q2 := eywa.Insert(
+		eywa.Row(testTable2_AgeField(20)),
+	).OnConflictIgnore(
+		testTable2_PkeyConstraint,
+	).Select(
+		testTable2_ID,
+	)
+	expected = `mutation insert_testTable2 {
+insert_testTable2(objects: [{age: 20}], on_conflict: {constraint: testTable2_pkey, update_columns: []}) {
+affected_rows
+returning {
+id
+}
+}
+}`
+	assert.Equal(t, expected, q2.Query())
+}
+
+func TestDistinctOnAndNestedOrderBy(t *testing.T) {
+	q := eywa.Get[testTable]().DistinctOn(
+		testTable_Name,
+		testTable_Age,
+	).OrderBy(
+		testTable_testTable2OrderBy(eywa.Desc(testTable2_Age)),
+		testTable_testTable2sAggregateOrderBy(eywa.CountOrder(eywa.Asc[testTable2])),
+		testTable_testTable2sAggregateOrderBy(eywa.AggregateOrder("max", eywa.Desc(testTable2_Age))),
+	).Select(testTable_Name)
+
+	expected := `query get_test_table {
+test_table(distinct_on: [name, age], order_by: [{testTable2: {age: desc}}, {testTable2s_aggregate: {count: asc}}, {testTable2s_aggregate: {max: {age: desc}}}]) {
+name
+}
+}`
+	assert.Equal(t, expected, q.Query())
+
+	expected = `query get_test_table($age_order: order_by!, $count_order: order_by!, $age_order_1: order_by!) {
+test_table(distinct_on: [name, age], order_by: [{testTable2: {age: $age_order}}, {testTable2s_aggregate: {count: $count_order}}, {testTable2s_aggregate: {max: {age: $age_order_1}}}]) {
+name
+}
+}`
+	assert.Equal(t, expected, eywa.Parameterize(q).Query())
+}
+
+func TestStreamQuery(t *testing.T) {
+	q := eywa.Stream(
+		testTable_IDVar(10),
+	).BatchSize(50).Desc().Where(
+		eywa.Eq[testTable](testTable_NameField("abc")),
+	).Select(testTable_Name)
+
+	expected := `subscription stream_test_table($testTable_ID: Int!) {
+test_table_stream(where: {name: {_eq: "abc"}}, batch_size: 50, cursor: {initial_value: {id: $testTable_ID}, ordering: DESC}) {
+name
+id
+}
+}`
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, map[string]interface{}{"testTable_ID": 10}, q.Variables())
+
+	expected = `subscription stream_test_table($name: String!, $batch_size: Int!, $testTable_ID: Int!) {
+test_table_stream(where: {name: {_eq: $name}}, batch_size: $batch_size, cursor: {initial_value: {id: $testTable_ID}, ordering: DESC}) {
+name
+id
+}
+}`
+	assert.Equal(t, expected, eywa.Parameterize(q).Query())
+
+	expected = `subscription stream_test_table {
+test_table_stream(batch_size: 100, cursor: {initial_value: {id: 0}, ordering: ASC}) {
+id
+}
+}`
+	assert.Equal(t, expected, eywa.Stream(testTable_IDField(0)).Select(testTable_ID).Query())
+}
+
+func TestBatchQuery(t *testing.T) {
+	q := eywa.Batch(
+		eywa.Fetch(eywa.Get[testTable]().Where(
+			eywa.Eq[testTable](testTable_NameVar("a")),
+		).Select(testTable_ID)),
+		eywa.Fetch(eywa.Get[testTable]().Where(
+			eywa.Eq[testTable](testTable_NameVar("b")),
+		).Select(testTable_ID)),
+		eywa.Fetch(eywa.Aggregate[testTable2]().Select(eywa.Count[testTable2]())),
+	)
+
+	expected := `query batch($testTable_Name: String!, $testTable_Name_1: String!) {
+test_table_0: test_table(where: {name: {_eq: $testTable_Name}}) {
+id
+}
+test_table_1: test_table(where: {name: {_eq: $testTable_Name_1}}) {
+id
+}
+testTable2_aggregate_2: testTable2_aggregate {
+aggregate {
+count
+}
+}
+}`
+	expectedVars := map[string]interface{}{
+		"testTable_Name":   "a",
+		"testTable_Name_1": "b",
+	}
+	assert.Equal(t, expected, q.Query())
+	assert.Equal(t, expectedVars, q.Variables())
+}
+
+func TestAliasQuery(t *testing.T) {
+	q := eywa.Batch(
+		eywa.Fetch(eywa.Get[testTable]().Select(
+			testTable_ID,
+			eywa.As("recent", testTable_testTable2sWith(
+				eywa.RelArgs[testTable2]().Limit(5),
+				testTable2_ID,
+			)),
+			eywa.As("adults", testTable_testTable2sWith(
+				eywa.RelArgs[testTable2]().Where(eywa.Gt[testTable2](testTable2_AgeField(18))),
+				testTable2_ID,
+			)),
+		)).As("tables"),
+		eywa.Fetch(eywa.Get[testTable]().Select(testTable_ID)),
+	)
+
+	expected := `query batch {
+tables: test_table {
+recent: testTable2s(limit: 5) {
+id
+}
+adults: testTable2s(where: {age: {_gt: 18}}) {
+id
+}
+id
+}
+test_table_1: test_table {
+id
+}
+}`
+	assert.Equal(t, expected, q.Query())
+}
+
+var (
+	testTable2Fields = eywa.NewFragment("testTable2Fields", testTable2_ID, testTable2_Age)
+	testTableFields  = eywa.NewFragment(
+		"testTableFields",
+		testTable_ID,
+		testTable_Name,
+		testTable_testTable2s(testTable2Fields.Spread()),
+	)
+)
+
+func TestFragmentQuery(t *testing.T) {
+	get := eywa.Get[testTable]().Select(testTableFields.Spread(), testTable_Age)
+	expected := `query get_test_table {
+test_table {
+age
+...testTableFields
+}
+}
+fragment testTableFields on test_table {
+id
+name
+testTable2s {
+...testTable2Fields
+}
+}
+fragment testTable2Fields on testTable2 {
+id
+age
+}`
+	assert.Equal(t, expected, get.Query())
+
+	id := uuid.New()
+	insert := eywa.InsertOne(testTable2_IDField(id)).Select(testTable2Fields.Spread())
+	expected = fmt.Sprintf(`mutation insert_testTable2_one {
+insert_testTable2_one(object: {id: "%s"}) {
+...testTable2Fields
+}
+}
+fragment testTable2Fields on testTable2 {
+id
+age
+}`, id)
+	assert.Equal(t, expected, insert.Query())
+
+	update := eywa.Mutation(
+		eywa.Into(eywa.Update[testTable]().Set(testTable_NameField("a")).Select(testTableFields.Spread()), new([]testTable)),
+		eywa.Into(eywa.Update[testTable2]().Set(testTable2_AgeField(1)).Select(testTable2Fields.Spread()), new([]testTable2)),
+	)
+	expected = `mutation atomic {
+update_test_table_0: update_test_table(where: {_not: {}}, _set: {name: "a"}) {
+returning {
+...testTableFields
+}
+}
+update_testTable2_1: update_testTable2(where: {_not: {}}, _set: {age: 1}) {
+returning {
+...testTable2Fields
+}
+}
+}
+fragment testTableFields on test_table {
+id
+name
+testTable2s {
+...testTable2Fields
+}
+}
+fragment testTable2Fields on testTable2 {
+id
+age
+}`
+	assert.Equal(t, expected, update.Query())
+
+	// a different fragment with the same name is renamed in the operation
+	ids := eywa.NewFragment("testTable2Fields", testTable2_ID)
+	conflict := eywa.Get[testTable2]().Select(testTable2Fields.Spread(), ids.Spread())
+	expected = `query get_testTable2 {
+testTable2 {
+...testTable2Fields
+...testTable2Fields_1
+}
+}
+fragment testTable2Fields on testTable2 {
+id
+}
+fragment testTable2Fields_1 on testTable2 {
+id
+age
+}`
+	assert.Equal(t, expected, conflict.Query())
+	assert.Panics(t, func() { eywa.NewFragment("invalid name", testTable2_ID) })
+
+	// the variables of the selection of a fragment are declared in the
+	// operation spreading it
+	adults := eywa.NewFragment(
+		"adults",
+		testTable_testTable2sWith(eywa.RelArgs[testTable2]().Where(eywa.Gt[testTable2](testTable2_AgeVar(18))), testTable2_ID),
+	)
+	withVars := eywa.Get[testTable]().Select(adults.Spread())
+	expected = `query get_test_table($testTable2_Age: Int!) {
+test_table {
+...adults
+}
+}
+fragment adults on test_table {
+testTable2s(where: {age: {_gt: $testTable2_Age}}) {
+id
+}
+}`
+	assert.Equal(t, expected, withVars.Query())
+	assert.Equal(t, map[string]interface{}{"testTable2_Age": 18}, withVars.Variables())
+}
+
+func TestDirectiveQuery(t *testing.T) {
+	q := func(isAdmin bool) eywa.GetQuery[testTable] {
+		admin := eywa.QueryVar("admin", eywa.BooleanVar(isAdmin))
+		return eywa.Get[testTable]().Select(
+			testTable_ID,
+			eywa.Include(testTable_Name, admin),
+			eywa.Skip(
+				testTable_testTable2sWith(
+					eywa.RelArgs[testTable2]().Where(eywa.Gt[testTable2](testTable2_AgeField(18))),
+					testTable2_ID,
+				),
+				admin,
+			),
+			eywa.Include(testTableFields.Spread(), eywa.QueryVar("details", eywa.BooleanVar(false))),
+		)
+	}
+
+	expected := `query get_test_table($admin: Boolean!, $details: Boolean!) {
+test_table {
+name @include(if: $admin)
+testTable2s(where: {age: {_gt: 18}}) @skip(if: $admin) {
+id
+}
+...testTableFields @include(if: $details)
+id
+}
+}
+fragment testTableFields on test_table {
+id
+name
+testTable2s {
+...testTable2Fields
+}
+}
+fragment testTable2Fields on testTable2 {
+id
+age
+}`
+	assert.Equal(t, expected, q(true).Query())
+	assert.Equal(t, expected, q(false).Query())
+	assert.Equal(t, map[string]interface{}{"admin": true, "details": false}, q(true).Variables())
+	assert.Equal(t, map[string]interface{}{"admin": false, "details": false}, q(false).Variables())
+
+	assert.Panics(t, func() { eywa.Include(testTable_Name, eywa.QueryVar("admin", eywa.StringVar("true"))) })
+	assert.NotContains(t, q(true).MarshalGQL(), "\x00")
+
+	// a variable shared by directives is declared once, and can't be given
+	// different values
+	shared := eywa.Get[testTable]().Select(
+		eywa.Include(testTable_Name, eywa.QueryVar("adm", eywa.BooleanVar(true))),
+		eywa.Skip(eywa.As("years", testTable_Age), eywa.QueryVar("adm", eywa.BooleanVar(true))),
+	)
+	expected = `query get_test_table($adm: Boolean!) {
+test_table {
+years: age @skip(if: $adm)
+name @include(if: $adm)
+}
+}`
+	assert.Equal(t, expected, shared.Query())
+	assert.Equal(t, map[string]interface{}{"adm": true}, shared.Variables())
+	conflicting := eywa.Get[testTable]().Select(
+		eywa.Include(testTable_Name, eywa.QueryVar("adm", eywa.BooleanVar(true))),
+		eywa.Skip(testTable_Age, eywa.QueryVar("adm", eywa.BooleanVar(false))),
+	)
+	assert.Panics(t, func() { conflicting.Query() })
+	whereConflict := eywa.Get[testTable]().Where(
+		eywa.Eq[testTable](testTable_NameVar("adm")),
+	).Select(eywa.Include(testTable_ID, eywa.QueryVar("testTable_Name", eywa.BooleanVar(true))))
+	assert.Panics(t, func() { whereConflict.Query() })
+
+	// directives stack, in the order they're given
+	stacked := eywa.Get[testTable]().Select(
+		eywa.Skip(eywa.Include(testTable_Name, eywa.QueryVar("a", eywa.BooleanVar(true))), eywa.QueryVar("b", eywa.BooleanVar(false))),
+	)
+	expected = `query get_test_table($a: Boolean!, $b: Boolean!) {
+test_table {
+name @include(if: $a) @skip(if: $b)
+}
+}`
+	assert.Equal(t, expected, stacked.Query())
+
+	// the variables of the directives in a fragment can be given when
+	// spreading it
+	names := eywa.NewFragment("names", eywa.Include(testTable_Name, eywa.QueryVar("withName", eywa.BooleanVar(false))))
+	spread := func(withName bool) eywa.GetQuery[testTable] {
+		return eywa.Get[testTable]().Select(names.Spread(eywa.QueryVar("withName", eywa.BooleanVar(withName))))
+	}
+	unbound := eywa.Get[testTable]().Select(names.Spread())
+	expected = `query get_test_table($withName: Boolean!) {
+test_table {
+...names
+}
+}
+fragment names on test_table {
+name @include(if: $withName)
+}`
+	assert.Equal(t, expected, unbound.Query())
+	assert.Equal(t, expected, spread(true).Query())
+	assert.Equal(t, map[string]interface{}{"withName": false}, unbound.Variables())
+	assert.Equal(t, map[string]interface{}{"withName": true}, spread(true).Variables())
+}
@@ -9,18 +9,19 @@ import (
 
 //go:generate ../eywagen -types testTable,testTable2
 type testTable struct {
-	Name       string                   `json:"name"`
-	Age        *int                     `json:"age"`
-	ID         int                      `json:"id,omitempty",eywa:"pkey"`
-	IDd        int32                    `json:"idd,omitempty"`
-	custom     *customType              `json:"custom"`
-	customArr  []*customType            `json:"customarr"`
-	testTable2 *testTable2              `json:"testTable2"`
-	JsonBCol   jsonbcol                 `json:"jsonb_col"`
-	Status     eywa.Enum[status]        `json:"status"`
-	Generic    GenericType[string, int] `json:"generic_type"`
-	ArrayCol   []string                 `json:"testarr"`
-	timestamp  time.Time                `json:"timestamp"`
+	Name        string                   `json:"name"`
+	Age         *int                     `json:"age"`
+	ID          int                      `json:"id,omitempty" eywa:"pkey"`
+	IDd         int32                    `json:"idd,omitempty"`
+	custom      *customType              `json:"custom"`
+	customArr   []*customType            `json:"customarr"`
+	testTable2  *testTable2              `json:"testTable2"`
+	testTable2s []*testTable2            `json:"testTable2s"`
+	JsonBCol    jsonbcol                 `json:"jsonb_col" eywa:"type=jsonb"`
+	Status      eywa.Enum[status]        `json:"status" eywa:"type=status_enum"`
+	Generic     GenericType[string, int] `json:"generic_type"`
+	ArrayCol    []string                 `json:"testarr"`
+	timestamp   time.Time                `json:"timestamp"`
 }
 
 type status string
@@ -42,7 +43,7 @@ func (t testTable) ModelName() string {
 type customType struct{}
 
 type testTable2 struct {
-	ID  uuid.UUID `json:"id",eywa:"pkey"`
+	ID  uuid.UUID `json:"id" eywa:"pkey"`
 	Age int       `json:"age"`
 }
 
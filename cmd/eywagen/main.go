@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"go/token"
 	"go/types"
 	"os"
 	re "regexp"
@@ -22,7 +23,10 @@ func usage() {
 	fmt.Fprint(os.Stderr, "\teywagen -types <comma separated list of type names>")
 }
 
-var tagPattern = re.MustCompile(`json:"([^"]+)"`)
+var (
+	tagPattern     = re.MustCompile(`json:"([^"]+)"`)
+	eywaTagPattern = re.MustCompile(`eywa:"([^"]+)"`)
+)
 
 const (
 	genHeader           = "// generated by eywa. DO NOT EDIT. Any changes will be overwritten.\npackage "
@@ -31,7 +35,7 @@ const (
 func %sField(val %s) eywa.Field[%s] {
 	return eywa.Field[%s]{
 		Name: "%s",
-		Value: val,
+		Value: val,%s
 	}
 }
 `
@@ -42,6 +46,21 @@ func %sVar(val %s) eywa.Field[%s] {
 		Value: eywa.QueryVar("%s", %s[%s](val)),
 	}
 }
+`
+	modelListFunc = `
+func %sIn(vals ...%s) *eywa.WhereExpr {
+	return eywa.In(eywa.Field[%s]{
+		Name: "%s",
+		Value: vals,%s
+	})
+}
+
+func %sNin(vals ...%s) *eywa.WhereExpr {
+	return eywa.Nin(eywa.Field[%s]{
+		Name: "%s",
+		Value: vals,%s
+	})
+}
 `
 	modelVarFunc = `
 func %sVar[T interface{%s;eywa.TypedValue}](val %s) eywa.Field[%s] {
@@ -52,16 +71,43 @@ func %sVar[T interface{%s;eywa.TypedValue}](val %s) eywa.Field[%s] {
 }
 `
 
+	modelPkFunc = `
+func %s_ByPk(%s) eywa.PrimaryKey[%s] {
+	return eywa.PrimaryKey[%s]{%s
+	}
+}
+`
 	modelRelationshipNameFunc = `
-func %s(subField eywa.FieldName[%s], subFields ...eywa.FieldName[%s]) eywa.FieldName[%s] {
-	buf := bytes.NewBuffer([]byte("%s {\n"))
-	buf.WriteString(string(subField))
-	for _, f := range subFields {
-		buf.WriteString("\n")
-		buf.WriteString(string(f))
-	}
-	buf.WriteString("\n}")
-	return eywa.FieldName[%s](buf.String())
+func %s(subField eywa.Selection[%s], subFields ...eywa.Selection[%s]) eywa.Selection[%s] {
+	return eywa.Relationship[%s, %s]("%s", eywa.RelArgs[%s](), subField, subFields...)
+}
+`
+	modelRelationshipWithArgsNameFunc = `
+func %sWith(args eywa.RelationshipArgs[%s], subField eywa.Selection[%s], subFields ...eywa.Selection[%s]) eywa.Selection[%s] {
+	return eywa.Relationship[%s, %s]("%s", args, subField, subFields...)
+}
+`
+	modelRelationshipInsertFunc = `
+func %sInsert(data eywa.%s[%s]) eywa.Field[%s] {
+	return eywa.Field[%s]{
+		Name:  "%s",
+		Value: data,
+	}
+}
+`
+	modelRelationshipOrderByFunc = `
+func %sOrderBy(ob eywa.OrderByExpr) eywa.OrderByExpr {
+	return eywa.OrderByRel[%s, %s]("%s", ob)
+}
+`
+	modelRelationshipAggregateOrderByFunc = `
+func %sAggregateOrderBy(ob eywa.OrderByExpr) eywa.OrderByExpr {
+	return eywa.OrderByAggregate[%s, %s]("%s", ob)
+}
+`
+	modelRelationshipWhereFunc = `
+func %sWhere(w *eywa.WhereExpr) *eywa.WhereExpr {
+	return eywa.Rel[%s, %s]("%s", w)
 }
 `
 )
@@ -147,6 +193,8 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 	contents.content.WriteString("\n")
 	contents.content.WriteString(pkeyConstraint(typeName))
 	recurseParse := make([]string, 0, typeStruct.NumFields())
+	pkParams := make([]string, 0, 1)
+	pkFields := bytes.NewBufferString("")
 	for i := 0; i < typeStruct.NumFields(); i++ {
 		tag := tagPattern.FindStringSubmatch(typeStruct.Tag(i))
 		if tag == nil {
@@ -168,15 +216,29 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 			fieldTypeName = fieldTypeNameFull[1:]
 		}
 
+		columnType := ""
+		if t := columnGqlType(typeStruct.Tag(i), fieldType); t != "" {
+			columnType = fmt.Sprintf("\n\t\tType: %q,", t)
+		}
+
+		if isPkey(typeStruct.Tag(i)) {
+			param := pkParamName(fieldName, len(pkParams))
+			pkParams = append(pkParams, fmt.Sprintf("%s %s", param, fieldTypeNameFull))
+			pkFields.WriteString(fmt.Sprintf("\n\t\t%s_%sField(%s),", typeName, field.Name(), param))
+		}
+
 		// *struct -> struct, *[] -> [], *int -> int, etc
 		if ptr, ok := fieldType.(*types.Pointer); ok {
 			fieldType = ptr.Elem()
 		}
 		// []*x -> *x, []x -> x
+		isList := false
 		if slice, ok := fieldType.(*types.Slice); ok {
 			fieldType = slice.Elem()
+			isList = true
 		} else if array, ok := fieldType.(*types.Array); ok {
 			fieldType = array.Elem()
+			isList = true
 		}
 		// struct -> *struct
 		var fieldGqlType string
@@ -196,15 +258,63 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 		case *types.Pointer:
 			fieldMethodSet := types.NewMethodSet(fieldType)
 			if m := fieldMethodSet.Lookup(pkg, "ModelName"); m != nil && m.Type().String() == "func() string" {
-				contents.importsMap["bytes"] = true
+				// the related model, without the slice of array relationships
+				_, fieldTypeName := parseFieldTypeName(fieldType.Elem().String(), pkg.Path())
 				contents.content.WriteString(fmt.Sprintf(
 					modelRelationshipNameFunc,
 					fmt.Sprintf("%s_%s", typeName, field.Name()),
 					fieldTypeName,
 					fieldTypeName,
 					typeName,
+					typeName,
+					fieldTypeName,
 					fieldName,
+					fieldTypeName,
+				))
+				// object relationships take no arguments
+				if isList {
+					contents.content.WriteString(fmt.Sprintf(
+						modelRelationshipWithArgsNameFunc,
+						fmt.Sprintf("%s_%s", typeName, field.Name()),
+						fieldTypeName,
+						fieldTypeName,
+						fieldTypeName,
+						typeName,
+						typeName,
+						fieldTypeName,
+						fieldName,
+					))
+				}
+				nestedInsertType := "NestedObjectInsert"
+				if isList {
+					nestedInsertType = "NestedArrayInsert"
+				}
+				contents.content.WriteString(fmt.Sprintf(
+					modelRelationshipInsertFunc,
+					fmt.Sprintf("%s_%s", typeName, field.Name()),
+					nestedInsertType,
+					fieldTypeName,
 					typeName,
+					typeName,
+					fieldName,
+				))
+				contents.content.WriteString(fmt.Sprintf(
+					modelRelationshipWhereFunc,
+					fmt.Sprintf("%s_%s", typeName, field.Name()),
+					typeName,
+					fieldTypeName,
+					fieldName,
+				))
+				relationshipOrderByFunc := modelRelationshipOrderByFunc
+				if isList {
+					relationshipOrderByFunc = modelRelationshipAggregateOrderByFunc
+				}
+				contents.content.WriteString(fmt.Sprintf(
+					relationshipOrderByFunc,
+					fmt.Sprintf("%s_%s", typeName, field.Name()),
+					typeName,
+					fieldTypeName,
+					fieldName,
 				))
 				recurseParse = append(recurseParse, fieldTypeName)
 			} else {
@@ -221,6 +331,7 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 					typeName,
 					typeName,
 					fieldName,
+					columnType,
 				))
 				if fieldScalarGqlType != "" {
 					contents.content.WriteString(fmt.Sprintf(
@@ -261,6 +372,7 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 				typeName,
 				typeName,
 				fieldName,
+				columnType,
 			))
 			if fieldScalarGqlType != "" {
 				contents.content.WriteString(fmt.Sprintf(
@@ -274,6 +386,19 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 					fmt.Sprintf("eywa.%sVar", fieldScalarGqlType),
 					fieldTypeNameFull,
 				))
+				contents.content.WriteString(fmt.Sprintf(
+					modelListFunc,
+					fmt.Sprintf("%s_%s", typeName, field.Name()),
+					fieldTypeName,
+					typeName,
+					fieldName,
+					columnType,
+					fmt.Sprintf("%s_%s", typeName, field.Name()),
+					fieldTypeName,
+					typeName,
+					fieldName,
+					columnType,
+				))
 			} else if fieldGqlType != "" {
 				contents.content.WriteString(fmt.Sprintf(
 					modelVarFunc,
@@ -288,6 +413,16 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 			}
 		}
 	}
+	if len(pkParams) > 0 {
+		contents.content.WriteString(fmt.Sprintf(
+			modelPkFunc,
+			typeName,
+			strings.Join(pkParams, ", "),
+			typeName,
+			typeName,
+			pkFields.String(),
+		))
+	}
 	for _, t := range recurseParse {
 		if err := parseType(t, pkg, contents); err != nil {
 			return err
@@ -296,6 +431,81 @@ func parseType(typeName string, pkg *types.Package, contents *fileContent) error
 	return nil
 }
 
+func isPkey(tag string) bool {
+	eywaTag := eywaTagPattern.FindStringSubmatch(tag)
+	if eywaTag == nil {
+		return false
+	}
+	for _, v := range strings.Split(eywaTag[1], ",") {
+		if v == "pkey" {
+			return true
+		}
+	}
+	return false
+}
+
+// columnGqlType returns the graphql type of the column of a field, from the
+// type option of its eywa tag, eg. `eywa:"type=bigint"`, or else from its go
+// type if it maps to a graphql type, see columnGqlTypes. Otherwise it returns
+// "", and eywa.Parameterize panics on the values of the field, as they can't
+// be lifted into variables. Enum and jsonb columns always need the type
+// option.
+func columnGqlType(tag string, fieldType types.Type) string {
+	if eywaTag := eywaTagPattern.FindStringSubmatch(tag); eywaTag != nil {
+		for _, v := range strings.Split(eywaTag[1], ",") {
+			if t, ok := strings.CutPrefix(v, "type="); ok {
+				return t
+			}
+		}
+	}
+	if ptr, ok := fieldType.(*types.Pointer); ok {
+		fieldType = ptr.Elem()
+	}
+	if slice, ok := fieldType.(*types.Slice); ok {
+		return columnGqlArrayTypes[slice.Elem().String()]
+	}
+	return columnGqlTypes[fieldType.String()]
+}
+
+// columnGqlTypes maps go types to the graphql types of the columns they are
+// stored in by default. Fields stored in other columns, eg. an int64 in an
+// integer column, need a type option in their eywa tag.
+var columnGqlTypes = map[string]string{
+	"bool":                        "Boolean",
+	"int":                         "Int",
+	"int32":                       "Int",
+	"int64":                       "bigint",
+	"float32":                     "float4",
+	"float64":                     "float8",
+	"string":                      "String",
+	"time.Time":                   "timestamptz",
+	"github.com/google/uuid.UUID": "uuid",
+}
+
+// columnGqlArrayTypes maps the element types of go slices to the graphql types
+// of the postgres array columns they are stored in by default, eg. _text for
+// text[].
+var columnGqlArrayTypes = map[string]string{
+	"bool":                        "_bool",
+	"int":                         "_int4",
+	"int32":                       "_int4",
+	"int64":                       "_int8",
+	"float32":                     "_float4",
+	"float64":                     "_float8",
+	"string":                      "_text",
+	"time.Time":                   "_timestamptz",
+	"github.com/google/uuid.UUID": "_uuid",
+}
+
+// pkParamName returns a valid go identifier for the column name, to be used as
+// a parameter name in the generated <Model>_ByPk function.
+func pkParamName(column string, i int) string {
+	if token.IsIdentifier(column) {
+		return column
+	}
+	return fmt.Sprintf("pk%d", i)
+}
+
 func writeToFile(filename string, contents *fileContent) error {
 	f, err := os.Create(filename)
 	if err != nil {
@@ -0,0 +1,209 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+func Delete[M Model, MP ModelPtr[M]]() DeleteQueryBuilder[M] {
+	return DeleteQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+		},
+	}
+}
+
+type DeleteQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (dq DeleteQueryBuilder[M]) Where(w *WhereExpr) DeleteQueryBuilder[M] {
+	dq.where = &where{w}
+	return dq
+}
+
+func (dq *DeleteQueryBuilder[M]) MarshalGQL() string {
+	return dq.marshalGQL(nil)
+}
+
+func (dq *DeleteQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	if dq.where == nil {
+		dq.where = &where{Not(&WhereExpr{})}
+	}
+	return fmt.Sprintf(
+		"delete_%s",
+		dq.QuerySkeleton.marshalGQL(vs),
+	)
+}
+
+func (dq DeleteQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) DeleteQuery[M] {
+	return DeleteQuery[M]{
+		dq:     &dq,
+		fields: append(fields, field),
+	}
+}
+
+type DeleteQuery[M Model] struct {
+	dq     *DeleteQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (dq DeleteQuery[M]) MarshalGQL() string {
+	return dq.marshalGQL(nil)
+}
+
+func (dq DeleteQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\naffected_rows\nreturning {\n%s\n}\n}",
+		dq.dq.marshalGQL(vs),
+		selectionArray[M](dq.fields).marshalGQL(vs),
+	)
+}
+
+func (dq DeleteQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := dq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation delete_%s%s {\n%s\n}",
+		dq.dq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (dq DeleteQuery[M]) Query() string {
+	return operationQuery(dq)
+}
+
+func (dq DeleteQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(dq)
+}
+
+func (dq DeleteQuery[M]) Exec(client *Client) (*MutationResponse[M], error) {
+	return dq.ExecWithContext(context.Background(), client)
+}
+
+func (dq DeleteQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*MutationResponse[M], error) {
+	return execQuery[M, *MutationResponse[M]](ctx, client, dq, fmt.Sprintf("delete_%s", dq.dq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of MutationResponse, eg. into a
+// struct with fields for aliased fields.
+func (dq DeleteQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return dq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (dq DeleteQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, dq, dq.mutationRoot(), dst)
+}
+
+func (dq DeleteQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("delete_%s", dq.dq.ModelName)
+}
+
+func (dq DeleteQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (dq DeleteQuery[M]) decodeMutationResult(data json.RawMessage) (*MutationResponse[M], error) {
+	return decodeResult[*MutationResponse[M]](data)
+}
+
+func DeleteByPk[M Model, MP ModelPtr[M]](pk PrimaryKey[M]) DeleteByPkQueryBuilder[M] {
+	return DeleteByPkQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				pk: &primaryKey[M]{FieldArray[M](pk)},
+			},
+		},
+	}
+}
+
+type DeleteByPkQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (dq *DeleteByPkQueryBuilder[M]) MarshalGQL() string {
+	return dq.marshalGQL(nil)
+}
+
+func (dq *DeleteByPkQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"delete_%s_by_pk%s",
+		dq.QuerySkeleton.ModelName,
+		dq.queryArgs.marshalGQL(vs),
+	)
+}
+
+func (dq DeleteByPkQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) DeleteByPkQuery[M] {
+	return DeleteByPkQuery[M]{
+		dq:     &dq,
+		fields: append(fields, field),
+	}
+}
+
+type DeleteByPkQuery[M Model] struct {
+	dq     *DeleteByPkQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (dq DeleteByPkQuery[M]) MarshalGQL() string {
+	return dq.marshalGQL(nil)
+}
+
+func (dq DeleteByPkQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\n%s\n}",
+		dq.dq.marshalGQL(vs),
+		selectionArray[M](dq.fields).marshalGQL(vs),
+	)
+}
+
+func (dq DeleteByPkQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := dq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation delete_%s_by_pk%s {\n%s\n}",
+		dq.dq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (dq DeleteByPkQuery[M]) Query() string {
+	return operationQuery(dq)
+}
+
+func (dq DeleteByPkQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(dq)
+}
+
+func (dq DeleteByPkQuery[M]) Exec(client *Client) (*M, error) {
+	return dq.ExecWithContext(context.Background(), client)
+}
+
+func (dq DeleteByPkQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*M, error) {
+	return execQuery[M, *M](ctx, client, dq, fmt.Sprintf("delete_%s_by_pk", dq.dq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields.
+func (dq DeleteByPkQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return dq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (dq DeleteByPkQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, dq, dq.mutationRoot(), dst)
+}
+
+func (dq DeleteByPkQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("delete_%s_by_pk", dq.dq.ModelName)
+}
+
+func (dq DeleteByPkQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (dq DeleteByPkQuery[M]) decodeMutationResult(data json.RawMessage) (*M, error) {
+	return decodeResult[*M](data)
+}
@@ -0,0 +1,76 @@
+package eywa
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Include selects the field only if the Boolean variable v is true, eg.
+//
+//	Get[User]().Select(User_ID, Include(User_Email, QueryVar("admin", BooleanVar(isAdmin))))
+//
+// selects email @include(if: $admin), declaring $admin: Boolean! in the
+// operation, so the query text stays the same for every value of it. The
+// directives of an operation using the same variable name share the variable,
+// which is declared once. It panics if they give it different values.
+//
+// Relationship selections can be made conditional too, in which case the
+// directive goes before their selection set, eg. orders(limit: 5) @include(if:
+// $admin) { id }.
+func Include[M Model](field Selection[M], v queryVar) Selection[M] {
+	return field.withDirective(newDirective("include", v))
+}
+
+// Skip selects the field only if the Boolean variable v is false. See Include.
+func Skip[M Model](field Selection[M], v queryVar) Selection[M] {
+	return field.withDirective(newDirective("skip", v))
+}
+
+type directive struct {
+	name string
+	v    queryVar
+}
+
+func newDirective(name string, v queryVar) directive {
+	if v.value.Type() != "Boolean!" || reflect.ValueOf(v.value.Value()).Kind() != reflect.Bool {
+		panic(fmt.Sprintf("eywa: directive variable %s should be a BooleanVar", v.name))
+	}
+	return directive{name, v}
+}
+
+type directives []directive
+
+// with returns a copy of ds with d added.
+func (ds directives) with(d directive) directives {
+	return append(append(directives{}, ds...), d)
+}
+
+// marshalGQL marshals the directives, each preceded by a space, to follow the
+// field they're on.
+func (ds directives) marshalGQL(vs *queryVarSet) string {
+	buf := strings.Builder{}
+	for _, d := range ds {
+		buf.WriteString(fmt.Sprintf(" @%s(if: %s)", d.name, vs.addShared(d.v)))
+	}
+	return buf.String()
+}
+
+// fieldSelection is a FieldName with directives.
+type fieldSelection[M Model] struct {
+	field      FieldName[M]
+	directives directives
+}
+
+func (f FieldName[M]) withDirective(d directive) Selection[M] {
+	return fieldSelection[M]{f, directives{d}}
+}
+
+func (fs fieldSelection[M]) selectionOf(M) {}
+func (fs fieldSelection[M]) withDirective(d directive) Selection[M] {
+	fs.directives = fs.directives.with(d)
+	return fs
+}
+func (fs fieldSelection[M]) marshalSelection(vs *queryVarSet) string {
+	return fmt.Sprintf("%s%s", fs.field, fs.directives.marshalGQL(vs))
+}
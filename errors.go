@@ -0,0 +1,155 @@
+package eywa
+
+import (
+	"errors"
+	"fmt"
+	re "regexp"
+	"strings"
+)
+
+var (
+	ErrConstraintViolation = errors.New("constraint violation")
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrValidationFailed    = errors.New("validation failed")
+	ErrNotFound            = errors.New("not found")
+	ErrDataException       = errors.New("data exception")
+	ErrJWTInvalid          = errors.New("invalid jwt")
+)
+
+// errorCodes maps the error codes set by hasura in extensions.code to the
+// sentinel errors matched by GraphQLError.
+var errorCodes = map[string]error{
+	"constraint-violation":    ErrConstraintViolation,
+	"permission-error":        ErrPermissionDenied,
+	"access-denied":           ErrPermissionDenied,
+	"validation-failed":       ErrValidationFailed,
+	"parse-failed":            ErrValidationFailed,
+	"not-found":               ErrNotFound,
+	"not-exists":              ErrNotFound,
+	"data-exception":          ErrDataException,
+	"invalid-jwt":             ErrJWTInvalid,
+	"jwt-invalid":             ErrJWTInvalid,
+	"jwt-invalid-claims":      ErrJWTInvalid,
+	"jwt-missing-role-claims": ErrJWTInvalid,
+}
+
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+	Locations  []GraphQLErrorLocation `json:"locations,omitempty"`
+}
+
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// Code returns the error code set by the graphql server in extensions.code.
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// Path returns the path to the part of the query which caused the error, as
+// set by the graphql server in extensions.path.
+func (e GraphQLError) Path() string {
+	path, _ := e.Extensions["path"].(string)
+	return path
+}
+
+// Is reports whether the error code of e maps to target, so that errors.Is can
+// be used to check for ErrConstraintViolation, ErrPermissionDenied, etc.
+func (e GraphQLError) Is(target error) bool {
+	err, ok := errorCodes[e.Code()]
+	return ok && err == target
+}
+
+var constraintPattern = re.MustCompile(`constraint "([^"]+)"`)
+
+// ConstraintViolationError is a constraint-violation error, carrying the name
+// of the violated constraint. It matches ErrConstraintViolation with errors.Is.
+type ConstraintViolationError[M Model] struct {
+	Constraint Constraint[M]
+	GraphQLError
+}
+
+func (e *ConstraintViolationError[M]) Unwrap() error {
+	return e.GraphQLError
+}
+
+func newConstraintViolationError[M Model](e GraphQLError) *ConstraintViolationError[M] {
+	msg := e.Message
+	if internal, ok := e.Extensions["internal"].(map[string]interface{}); ok {
+		if internalErr, ok := internal["error"].(map[string]interface{}); ok {
+			if internalMsg, ok := internalErr["message"].(string); ok {
+				msg = fmt.Sprintf("%s %s", msg, internalMsg)
+			}
+		}
+	}
+
+	var constraint Constraint[M]
+	if match := constraintPattern.FindStringSubmatch(msg); match != nil {
+		constraint = Constraint[M](match[1])
+	}
+	return &ConstraintViolationError[M]{
+		Constraint:   constraint,
+		GraphQLError: e,
+	}
+}
+
+// GraphQLErrors is returned by Exec when the graphql response contains errors.
+// Use errors.As to inspect the individual errors.
+type GraphQLErrors struct {
+	Errors []GraphQLError
+	errs   []error
+}
+
+// newGraphQLErrors classifies the errors of a response to queries on M.
+func newGraphQLErrors[M Model](gqlErrs []GraphQLError) *GraphQLErrors {
+	return classifyGraphQLErrors(gqlErrs, classifyGraphQLError[M])
+}
+
+func classifyGraphQLErrors(gqlErrs []GraphQLError, classify func(GraphQLError) error) *GraphQLErrors {
+	errs := make([]error, 0, len(gqlErrs))
+	for _, gqlErr := range gqlErrs {
+		errs = append(errs, classify(gqlErr))
+	}
+	return &GraphQLErrors{
+		Errors: gqlErrs,
+		errs:   errs,
+	}
+}
+
+func classifyGraphQLError[M Model](e GraphQLError) error {
+	if e.Code() == "constraint-violation" {
+		return newConstraintViolationError[M](e)
+	}
+	return e
+}
+
+func (e *GraphQLErrors) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, gqlErr := range e.Errors {
+		if code := gqlErr.Code(); code != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", code, gqlErr.Message))
+		} else {
+			msgs = append(msgs, gqlErr.Message)
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *GraphQLErrors) Unwrap() []error {
+	if e.errs != nil {
+		return e.errs
+	}
+	errs := make([]error, 0, len(e.Errors))
+	for _, gqlErr := range e.Errors {
+		errs = append(errs, gqlErr)
+	}
+	return errs
+}
@@ -0,0 +1,168 @@
+package eywa_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imperfect-fourth/eywa"
+	"github.com/stretchr/testify/assert"
+)
+
+type testModel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (m testModel) ModelName() string {
+	return "test_model"
+}
+
+func (m testModel) TableName() string {
+	return "test_model"
+}
+
+const (
+	testModel_ID   eywa.FieldName[testModel] = "id"
+	testModel_Name eywa.FieldName[testModel] = "name"
+)
+
+func testModel_NameField(val string) eywa.Field[testModel] {
+	return eywa.Field[testModel]{
+		Name:  "name",
+		Value: val,
+		Type:  "String",
+	}
+}
+
+func newTestServer(resp string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(resp))
+	}))
+}
+
+func TestMutationGraphQLErrors(t *testing.T) {
+	server := newTestServer(`{
+		"errors": [{
+			"message": "field 'nme' not found in type: 'test_model_insert_input'",
+			"extensions": {"path": "$.selectionSet.insert_test_model_one.args.object.nme", "code": "validation-failed"},
+			"locations": [{"line": 2, "column": 3}]
+		}]
+	}`)
+	defer server.Close()
+
+	resp, err := eywa.InsertOne(
+		testModel_NameField("abc"),
+	).Select(
+		testModel_ID,
+	).Exec(eywa.NewClient(server.URL, nil))
+
+	assert.Nil(t, resp)
+	var gqlErrs *eywa.GraphQLErrors
+	if assert.True(t, errors.As(err, &gqlErrs)) && assert.Len(t, gqlErrs.Errors, 1) {
+		gqlErr := gqlErrs.Errors[0]
+		assert.Equal(t, "validation-failed", gqlErr.Code())
+		assert.Equal(t, "$.selectionSet.insert_test_model_one.args.object.nme", gqlErr.Path())
+		assert.Equal(t, []eywa.GraphQLErrorLocation{{Line: 2, Column: 3}}, gqlErr.Locations)
+	}
+
+	var gqlErr eywa.GraphQLError
+	if assert.True(t, errors.As(err, &gqlErr)) {
+		assert.Equal(t, "field 'nme' not found in type: 'test_model_insert_input'", gqlErr.Message)
+	}
+}
+
+func TestUpdateGraphQLErrors(t *testing.T) {
+	server := newTestServer(`{"errors": [{"message": "first"}, {"message": "second"}]}`)
+	defer server.Close()
+
+	resp, err := eywa.Update[testModel]().Set(
+		testModel_NameField("abc"),
+	).Select(
+		testModel_ID,
+	).ExecWithContext(context.TODO(), eywa.NewClient(server.URL, nil))
+
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "first\nsecond")
+}
+
+func TestConstraintViolationError(t *testing.T) {
+	server := newTestServer(`{
+		"errors": [{
+			"message": "Uniqueness violation. duplicate key value violates unique constraint \"test_model_pkey\"",
+			"extensions": {"path": "$.selectionSet.insert_test_model_one.args.object", "code": "constraint-violation"}
+		}]
+	}`)
+	defer server.Close()
+
+	_, err := eywa.InsertOne(
+		testModel_NameField("abc"),
+	).Select(
+		testModel_ID,
+	).Exec(eywa.NewClient(server.URL, nil))
+
+	assert.ErrorIs(t, err, eywa.ErrConstraintViolation)
+	assert.NotErrorIs(t, err, eywa.ErrPermissionDenied)
+
+	var constraintErr *eywa.ConstraintViolationError[testModel]
+	if assert.True(t, errors.As(err, &constraintErr)) {
+		assert.Equal(t, eywa.Constraint[testModel]("test_model_pkey"), constraintErr.Constraint)
+	}
+
+	var gqlErr eywa.GraphQLError
+	if assert.True(t, errors.As(err, &gqlErr)) {
+		assert.Equal(t, "constraint-violation", gqlErr.Code())
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	tt := []struct {
+		code        string
+		expectedErr error
+	}{
+		{"permission-error", eywa.ErrPermissionDenied},
+		{"validation-failed", eywa.ErrValidationFailed},
+		{"not-found", eywa.ErrNotFound},
+		{"data-exception", eywa.ErrDataException},
+		{"invalid-jwt", eywa.ErrJWTInvalid},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.code, func(t *testing.T) {
+			server := newTestServer(`{"errors": [{"message": "error", "extensions": {"code": "` + tc.code + `"}}]}`)
+			defer server.Close()
+
+			_, err := eywa.Get[testModel]().Select(testModel_Name).Exec(eywa.NewClient(server.URL, nil))
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.NotErrorIs(t, err, eywa.ErrConstraintViolation)
+		})
+	}
+}
+
+func TestMutationConstraintViolationError(t *testing.T) {
+	server := newTestServer(`{
+		"errors": [{
+			"message": "Uniqueness violation. duplicate key value violates unique constraint \"test_model_pkey\"",
+			"extensions": {"path": "$.selectionSet.insert_test_model_one_1.args.object", "code": "constraint-violation"}
+		}]
+	}`)
+	defer server.Close()
+
+	var updated []testModel
+	var inserted *testModel
+	err := eywa.Mutation(
+		eywa.Into(eywa.Update[testModel]().Set(testModel_NameField("a")).Select(testModel_ID), &updated),
+		eywa.Into(eywa.InsertOne(testModel_NameField("b")).Select(testModel_ID), &inserted),
+	).Exec(eywa.NewClient(server.URL, nil))
+
+	assert.ErrorIs(t, err, eywa.ErrConstraintViolation)
+	var constraintErr *eywa.ConstraintViolationError[testModel]
+	if assert.True(t, errors.As(err, &constraintErr)) {
+		assert.Equal(t, eywa.Constraint[testModel]("test_model_pkey"), constraintErr.Constraint)
+	}
+	assert.Nil(t, updated)
+	assert.Nil(t, inserted)
+}
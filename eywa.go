@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type graphqlRequest struct {
@@ -12,11 +13,6 @@ type graphqlRequest struct {
 	Variables map[string]interface{} `json:"variables"`
 }
 
-type GraphQLError struct {
-	Message    string                 `json:"message"`
-	Extensions map[string]interface{} `json:"extensions"`
-}
-
 type Model interface {
 	ModelName() string
 	TableName() string
@@ -31,6 +27,25 @@ type FieldName[M Model] string
 
 type FieldNameArray[M Model] []FieldName[M]
 
+// As aliases the selected field, so that it's returned under the alias, eg.
+//
+//	As("recent_orders", User_OrdersWith(RelArgs[Order]().Limit(5), Order_ID))
+//
+// selects recent_orders: orders(limit: 5) { id }. The aliased field is decoded
+// into the field of M with the alias as its json name, if any. Use ExecInto to
+// decode it into any other value. It panics if alias isn't a valid graphql
+// name.
+func As[M Model](alias string, field Selection[M]) Selection[M] {
+	return aliasedSelection[M]{validAlias(alias), field}
+}
+
+func validAlias(alias string) string {
+	if !gqlNamePattern.MatchString(alias) {
+		panic(fmt.Sprintf("eywa: invalid alias %q", alias))
+	}
+	return alias
+}
+
 func (fa FieldNameArray[M]) MarshalGQL() string {
 	buf := bytes.NewBufferString("")
 	for i, f := range fa {
@@ -45,6 +60,18 @@ func (fa FieldNameArray[M]) MarshalGQL() string {
 type Field[M Model] struct {
 	Name  string
 	Value interface{}
+	// Type is the graphql type of the field, eg. bigint, as declared in the
+	// schema. The eywagen generated <Model>_<Field>Field functions set it from
+	// the type option of the eywa tag of the field, eg. `eywa:"type=bigint"`,
+	// or from its go type for scalar, time.Time, uuid.UUID and slice fields.
+	// Parameterize panics on values of fields without a Type.
+	Type string
+}
+
+// withType returns f with its Type set to typ.
+func (f Field[M]) withType(typ string) valueMarshaler {
+	f.Type = typ
+	return f
 }
 
 func (f Field[M]) GetName() string {
@@ -52,14 +79,39 @@ func (f Field[M]) GetName() string {
 }
 
 func (f Field[M]) GetValue() string {
+	return f.marshalValue(nil)
+}
+
+// valueMarshaler is implemented by values which may contain query variables.
+type valueMarshaler interface {
+	marshalValue(vs *queryVarSet) string
+}
+
+func (f Field[M]) marshalValue(vs *queryVarSet) string {
 	if var_, ok := f.Value.(queryVar); ok {
-		return fmt.Sprintf("$%s", var_.name)
+		return vs.add(var_)
+	}
+
+	if val, ok := f.Value.(valueMarshaler); ok {
+		return val.marshalValue(vs)
+	}
+
+	if vs.parameterizing() {
+		tv, ok := f.typedValue()
+		if !ok {
+			panic(fmt.Sprintf("eywa: can't parameterize the value of field %s, which has no Type", f.Name))
+		}
+		return vs.add(queryVar{f.Name, tv})
 	}
 
 	if val, ok := f.Value.(GQLMarshaler); ok {
 		return val.MarshalGQL()
 	}
 
+	if list, ok := marshalGQLList(f.Value); ok {
+		return list
+	}
+
 	val, _ := json.Marshal(f.Value)
 	vt := reflect.TypeOf(f.Value)
 	if vt.Kind() == reflect.Ptr {
@@ -71,6 +123,42 @@ func (f Field[M]) GetValue() string {
 	return string(val)
 }
 
+// marshalGQLList marshals slices of GQLMarshaler values, like enums, which can't
+// be marshalled as json.
+func marshalGQLList(val interface{}) (string, bool) {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", false
+	}
+	// nil slices are null
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return "", false
+	}
+	if !v.Type().Elem().Implements(gqlMarshalerType) {
+		return "", false
+	}
+	stringArr := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		stringArr = append(stringArr, v.Index(i).Interface().(GQLMarshaler).MarshalGQL())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(stringArr, ", ")), true
+}
+
+// typedValue returns the value of f typed with its Type, which is nullable for
+// nil, pointer and nil slice and map values, if it has one.
+func (f Field[M]) typedValue() (TypedValue, bool) {
+	if tv, ok := f.Value.(TypedValue); ok {
+		return tv, true
+	}
+	if f.Type == "" {
+		return nil, false
+	}
+	if v := reflect.ValueOf(f.Value); !v.IsValid() || v.Kind() == reflect.Ptr || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+		return scalarValue{f.Type, f.Value}, true
+	}
+	return scalarValue{fmt.Sprintf("%s!", f.Type), f.Value}, true
+}
+
 func (f Field[M]) GetRawValue() interface{} {
 	return f.Value
 }
@@ -78,6 +166,10 @@ func (f Field[M]) GetRawValue() interface{} {
 type FieldArray[M Model] []Field[M]
 
 func (fs FieldArray[M]) MarshalGQL() string {
+	return fs.marshalGQL(nil)
+}
+
+func (fs FieldArray[M]) marshalGQL(vs *queryVarSet) string {
 	buf := bytes.NewBufferString("")
 	for i, f := range fs {
 		if i > 0 {
@@ -85,11 +177,21 @@ func (fs FieldArray[M]) MarshalGQL() string {
 		}
 		buf.WriteString(f.GetName())
 		buf.WriteString(": ")
-		buf.WriteString(f.GetValue())
+		buf.WriteString(f.marshalValue(vs))
 	}
 	return buf.String()
 }
 
+// PrimaryKey holds the values of the primary key columns identifying a single
+// row of a model. eywagen generates a <Model>_ByPk function returning it for
+// models with fields tagged `eywa:"pkey"`.
+type PrimaryKey[M Model] FieldArray[M]
+
+func Pk[M Model](field Field[M], fields ...Field[M]) PrimaryKey[M] {
+	arr := PrimaryKey[M](fields)
+	return append(arr, field)
+}
+
 type Queryable interface {
 	Query() string
 	Variables() map[string]interface{}
@@ -97,13 +199,22 @@ type Queryable interface {
 
 type QuerySkeleton[M Model] struct {
 	ModelName string
-	queryVars queryVarArr
 	// fields    ModelFieldArr[M, FN, F]
 	queryArgs[M]
 }
 
 func (qs QuerySkeleton[M]) MarshalGQL() string {
-	return fmt.Sprintf("%s%s", qs.ModelName, qs.queryArgs.MarshalGQL())
+	return qs.marshalGQL(nil)
+}
+
+func (qs QuerySkeleton[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf("%s%s", qs.ModelName, qs.queryArgs.marshalGQL(vs))
 }
 
 type Constraint[M Model] string
+
+// MutationResponse is the response of mutations that can affect multiple rows.
+type MutationResponse[M Model] struct {
+	AffectedRows int `json:"affected_rows"`
+	Returning    []M `json:"returning"`
+}
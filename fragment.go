@@ -0,0 +1,126 @@
+package eywa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fragment is a named selection set of M, which can be shared by the selections
+// of any query, eg.
+//
+//	var PublicUser = NewFragment("PublicUser", User_ID, User_Name, User_ordersWith(...))
+//	Get[User]().Select(PublicUser.Spread(), User_Email)
+//
+// The operation then defines the fragment, as fragment PublicUser on user, and
+// spreads it where it's selected, as ...PublicUser.
+type Fragment[M Model] struct {
+	def *fragmentDef
+}
+
+type fragmentDef struct {
+	name      string
+	typeName  string
+	selection interface {
+		marshalGQL(vs *queryVarSet) string
+	}
+}
+
+// NewFragment defines a fragment with the selected fields, which can spread
+// other fragments too. The fragment is defined in the operations spreading it,
+// with the variables of its selection declared along with theirs. Different
+// fragments spread in an operation under the same name are renamed by suffixing
+// a number, eg. PublicUser_1.
+func NewFragment[M Model](name string, field Selection[M], fields ...Selection[M]) Fragment[M] {
+	if !gqlNamePattern.MatchString(name) {
+		panic(fmt.Sprintf("eywa: invalid fragment name %q", name))
+	}
+	return Fragment[M]{&fragmentDef{
+		name:      name,
+		typeName:  (*new(M)).ModelName(),
+		selection: selectionArray[M](append([]Selection[M]{field}, fields...)),
+	}}
+}
+
+func (f Fragment[M]) Name() string {
+	return f.def.name
+}
+
+// Spread selects the fields of the fragment. The variables of the directives in
+// the fragment, see Include, take the values given in NewFragment, unless vars
+// gives other values for them, eg.
+//
+//	var PublicUser = NewFragment("PublicUser", User_ID, Include(User_Email, QueryVar("admin", BooleanVar(false))))
+//	Get[User]().Select(PublicUser.Spread(QueryVar("admin", BooleanVar(isAdmin))))
+func (f Fragment[M]) Spread(vars ...queryVar) Selection[M] {
+	return fragmentSpread[M]{def: f.def, vars: vars}
+}
+
+type fragmentSpread[M Model] struct {
+	def        *fragmentDef
+	vars       []queryVar
+	directives directives
+}
+
+func (fs fragmentSpread[M]) selectionOf(M) {}
+func (fs fragmentSpread[M]) withDirective(d directive) Selection[M] {
+	fs.directives = fs.directives.with(d)
+	return fs
+}
+func (fs fragmentSpread[M]) marshalSelection(vs *queryVarSet) string {
+	for _, v := range fs.vars {
+		vs.override(v)
+	}
+	return fmt.Sprintf("...%s%s", vs.addFragment(fs.def), fs.directives.marshalGQL(vs))
+}
+
+// fragment is the definition of a fragment spread in an operation, under the
+// name it's defined with in it.
+type fragment struct {
+	name      string
+	def       *fragmentDef
+	selection string
+}
+
+func (f fragment) MarshalGQL() string {
+	return fmt.Sprintf("fragment %s on %s {\n%s\n}", f.name, f.def.typeName, f.selection)
+}
+
+// addFragment collects the definition of the spread fragment, and the ones it
+// spreads in turn, in the set, and returns the name to spread it with. A nil
+// set only returns the name.
+func (vs *queryVarSet) addFragment(def *fragmentDef) string {
+	if vs == nil {
+		return def.name
+	}
+	for _, f := range vs.fragments {
+		if f.def == def {
+			return f.name
+		}
+	}
+	if vs.fragmentNames == nil {
+		vs.fragmentNames = make(map[string]bool)
+	}
+
+	name := def.name
+	for i := 1; vs.fragmentNames[name]; i++ {
+		name = fmt.Sprintf("%s_%d", def.name, i)
+	}
+	vs.fragmentNames[name] = true
+	idx := len(vs.fragments)
+	// the fragment is added before marshalling its selection, so that it's
+	// spread by name if it spreads itself
+	vs.fragments = append(vs.fragments, fragment{name: name, def: def})
+	vs.fragments[idx].selection = def.selection.marshalGQL(vs)
+	return name
+}
+
+// marshalFragments marshals the definitions of the fragments in the set, to
+// follow the operation.
+func (vs *queryVarSet) marshalFragments() string {
+	buf := strings.Builder{}
+	for _, f := range vs.fragments {
+		buf.WriteString("\n")
+		buf.WriteString(f.MarshalGQL())
+	}
+	return buf.String()
+}
@@ -3,7 +3,6 @@ package eywa
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 )
 
@@ -20,8 +19,8 @@ type GetQueryBuilder[M Model] struct {
 	QuerySkeleton[M]
 }
 
-func (sq GetQueryBuilder[M]) DistinctOn(f FieldName[M]) GetQueryBuilder[M] {
-	sq.distinctOn = &distinctOn[M]{f}
+func (sq GetQueryBuilder[M]) DistinctOn(f FieldName[M], fs ...FieldName[M]) GetQueryBuilder[M] {
+	sq.distinctOn = &distinctOn[M]{append([]FieldName[M]{f}, fs...)}
 	return sq
 }
 
@@ -47,10 +46,14 @@ func (sq GetQueryBuilder[M]) Where(w *WhereExpr) GetQueryBuilder[M] {
 }
 
 func (sq GetQueryBuilder[M]) MarshalGQL() string {
-	return sq.QuerySkeleton.MarshalGQL()
+	return sq.marshalGQL(nil)
 }
 
-func (sq GetQueryBuilder[M]) Select(field FieldName[M], fields ...FieldName[M]) GetQuery[M] {
+func (sq GetQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return sq.QuerySkeleton.marshalGQL(vs)
+}
+
+func (sq GetQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) GetQuery[M] {
 	return GetQuery[M]{
 		sq:     &sq,
 		fields: append(fields, field),
@@ -59,27 +62,37 @@ func (sq GetQueryBuilder[M]) Select(field FieldName[M], fields ...FieldName[M])
 
 type GetQuery[M Model] struct {
 	sq     *GetQueryBuilder[M]
-	fields []FieldName[M]
+	fields []Selection[M]
 }
 
 func (sq GetQuery[M]) MarshalGQL() string {
+	return sq.marshalGQL(nil)
+}
+
+func (sq GetQuery[M]) marshalGQL(vs *queryVarSet) string {
 	return fmt.Sprintf(
 		"%s {\n%s\n}",
-		sq.sq.MarshalGQL(),
-		FieldNameArray[M](sq.fields).MarshalGQL(),
+		sq.sq.marshalGQL(vs),
+		selectionArray[M](sq.fields).marshalGQL(vs),
 	)
 }
 
-func (sq GetQuery[M]) Query() string {
+func (sq GetQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := sq.marshalGQL(vs)
 	return fmt.Sprintf(
-		"query get_%s {\n%s\n}",
+		"query get_%s%s {\n%s\n}",
 		sq.sq.ModelName,
-		sq.MarshalGQL(),
+		vs.MarshalGQL(),
+		body,
 	)
 }
 
+func (sq GetQuery[M]) Query() string {
+	return operationQuery(sq)
+}
+
 func (sq GetQuery[M]) Variables() map[string]interface{} {
-	return nil
+	return operationVariables(sq)
 }
 
 func (sq GetQuery[M]) Exec(client *Client) ([]M, error) {
@@ -87,29 +100,135 @@ func (sq GetQuery[M]) Exec(client *Client) ([]M, error) {
 }
 
 func (sq GetQuery[M]) ExecWithContext(ctx context.Context, client *Client) ([]M, error) {
-	respBytes, err := client.Do(ctx, sq)
-	if err != nil {
-		return nil, err
-	}
+	return execQuery[M, []M](ctx, client, sq, sq.sq.ModelName)
+}
 
-	type graphqlResponse struct {
-		Data   map[string][]M `json:"data"`
-		Errors []GraphQLError `json:"errors"`
-	}
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields, or a generic []map[string]interface{}.
+func (sq GetQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return sq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (sq GetQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, sq, sq.queryRoot(), dst)
+}
+
+func (sq GetQuery[M]) queryRoot() string {
+	return sq.sq.ModelName
+}
+
+func (sq GetQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
 
-	respObj := graphqlResponse{}
-	err = json.NewDecoder(respBytes).Decode(&respObj)
-	if err != nil {
-		return nil, err
+func (sq GetQuery[M]) decodeQueryResult(data json.RawMessage) ([]M, error) {
+	return decodeResult[[]M](data)
+}
+
+// Subscribe sends the rows matched by the query on the returned channel every
+// time they change, until ctx is cancelled. The error ending the subscription,
+// if any, eg. ErrSubscriptionRejected or ErrHandshakeFailed, is sent on the
+// error channel. See SubscriptionClient.
+func (sq GetQuery[M]) Subscribe(ctx context.Context, client *SubscriptionClient) (<-chan []M, <-chan error) {
+	return subscribe[M, []M](ctx, client, subscription{sq}, sq.sq.ModelName, nil)
+}
+
+func GetByPk[M Model, MP ModelPtr[M]](pk PrimaryKey[M]) GetByPkQueryBuilder[M] {
+	return GetByPkQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				pk: &primaryKey[M]{FieldArray[M](pk)},
+			},
+		},
 	}
+}
+
+type GetByPkQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (sq *GetByPkQueryBuilder[M]) MarshalGQL() string {
+	return sq.marshalGQL(nil)
+}
+
+func (sq *GetByPkQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s_by_pk%s",
+		sq.QuerySkeleton.ModelName,
+		sq.queryArgs.marshalGQL(vs),
+	)
+}
 
-	if len(respObj.Errors) > 0 {
-		gqlErrs := make([]error, 0, len(respObj.Errors))
-		for _, e := range respObj.Errors {
-			gqlErrs = append(gqlErrs, errors.New(e.Message))
-		}
-		return nil, errors.Join(gqlErrs...)
+func (sq GetByPkQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) GetByPkQuery[M] {
+	return GetByPkQuery[M]{
+		sq:     &sq,
+		fields: append(fields, field),
 	}
+}
+
+type GetByPkQuery[M Model] struct {
+	sq     *GetByPkQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (sq GetByPkQuery[M]) MarshalGQL() string {
+	return sq.marshalGQL(nil)
+}
+
+func (sq GetByPkQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\n%s\n}",
+		sq.sq.marshalGQL(vs),
+		selectionArray[M](sq.fields).marshalGQL(vs),
+	)
+}
+
+func (sq GetByPkQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := sq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"query get_%s_by_pk%s {\n%s\n}",
+		sq.sq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (sq GetByPkQuery[M]) Query() string {
+	return operationQuery(sq)
+}
+
+func (sq GetByPkQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(sq)
+}
+
+func (sq GetByPkQuery[M]) Exec(client *Client) (*M, error) {
+	return sq.ExecWithContext(context.Background(), client)
+}
+
+// ExecWithContext returns nil without an error if no row exists for the primary key.
+func (sq GetByPkQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*M, error) {
+	return execQuery[M, *M](ctx, client, sq, fmt.Sprintf("%s_by_pk", sq.sq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields, or a generic []map[string]interface{}.
+func (sq GetByPkQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return sq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (sq GetByPkQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, sq, sq.queryRoot(), dst)
+}
+
+func (sq GetByPkQuery[M]) queryRoot() string {
+	return fmt.Sprintf("%s_by_pk", sq.sq.ModelName)
+}
+
+func (sq GetByPkQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
 
-	return respObj.Data[sq.sq.ModelName], nil
+func (sq GetByPkQuery[M]) decodeQueryResult(data json.RawMessage) (*M, error) {
+	return decodeResult[*M](data)
 }
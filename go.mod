@@ -3,6 +3,7 @@ module github.com/imperfect-fourth/eywa
 go 1.22.1
 
 require (
+	github.com/coder/websocket v1.8.13
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/tools v0.30.0
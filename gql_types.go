@@ -1,5 +1,10 @@
 package eywa
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // type Type interface {
 // 	Type() string
 // }
@@ -70,6 +75,17 @@ func StringVar[T ~string](val T) TypedValue {
 func NullableStringVar[T ~*string](val T) TypedValue {
 	return scalarValue{"String", val}
 }
+
+// ListVar returns a list variable holding vals, typed as a list of the type of
+// the variables returned by elem, eg. ListVar(ids, IntVar[int]) is an [Int!]!.
+// A nil vals is an empty list.
+func ListVar[T any](vals []T, elem func(T) TypedValue) TypedValue {
+	var zero T
+	if vals == nil {
+		vals = []T{}
+	}
+	return scalarValue{fmt.Sprintf("[%s]!", elem(zero).Type()), vals}
+}
 func JSONVar(val interface{}) TypedValue {
 	return JSONValue{val}
 }
@@ -98,3 +114,5 @@ func (jv JSONBValue) Type() string {
 func (jv JSONBValue) Value() interface{} {
 	return jv.Val
 }
+
+var gqlMarshalerType = reflect.TypeOf((*GQLMarshaler)(nil)).Elem()
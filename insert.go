@@ -0,0 +1,269 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Row groups the fields of a single row to be inserted with Insert.
+func Row[M Model](field Field[M], fields ...Field[M]) FieldArray[M] {
+	arr := FieldArray[M](fields)
+	return append(arr, field)
+}
+
+// NestedObjectInsert is the data of a row of R inserted through an object
+// relationship, along with the row it's related to, in the same mutation.
+// eywagen generates a <Model>_<relationship>Insert function for every object
+// relationship field to use it as a field of the parent row, eg.
+//
+//	InsertOne(
+//		Order_TotalField(100),
+//		Order_UserInsert(eywa.ObjectInsert(eywa.Row(User_NameField("x")))),
+//	)
+//
+// The zero value inserts a row with the default values of its fields.
+type NestedObjectInsert[R Model] struct {
+	nestedInsert[R]
+}
+
+// NestedArrayInsert is the data of rows of R inserted through an array
+// relationship, along with the row they are related to, in the same mutation.
+// eywagen generates a <Model>_<relationship>Insert function for every array
+// relationship field to use it as a field of the parent row, eg.
+//
+//	InsertOne(
+//		User_NameField("x"),
+//		User_OrdersInsert(eywa.ArrayInsert(
+//			eywa.Row(Order_TotalField(100)),
+//			eywa.Row(Order_TotalField(200)),
+//		)),
+//	)
+//
+// The zero value inserts no rows.
+type NestedArrayInsert[R Model] struct {
+	nestedInsert[R]
+}
+
+type nestedInsert[R Model] struct {
+	rows       []FieldArray[R]
+	onConflict *onConflict[R]
+}
+
+// ObjectInsert inserts a single row through an object relationship.
+func ObjectInsert[R Model](row FieldArray[R]) NestedObjectInsert[R] {
+	return NestedObjectInsert[R]{nestedInsert[R]{
+		rows: []FieldArray[R]{row},
+	}}
+}
+
+// ArrayInsert inserts rows through an array relationship.
+func ArrayInsert[R Model](row FieldArray[R], rows ...FieldArray[R]) NestedArrayInsert[R] {
+	return NestedArrayInsert[R]{nestedInsert[R]{
+		rows: append([]FieldArray[R]{row}, rows...),
+	}}
+}
+
+func (ni NestedObjectInsert[R]) OnConflict(constraint Constraint[R], fields ...FieldName[R]) NestedObjectInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: fields,
+	}
+	return ni
+}
+
+// OnConflictWhere updates the fields of the conflicting row only if it matches w.
+func (ni NestedObjectInsert[R]) OnConflictWhere(constraint Constraint[R], w *WhereExpr, fields ...FieldName[R]) NestedObjectInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: fields,
+		where:         w,
+	}
+	return ni
+}
+
+// OnConflictIgnore leaves the conflicting row as it is.
+func (ni NestedObjectInsert[R]) OnConflictIgnore(constraint Constraint[R]) NestedObjectInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: FieldNameArray[R]{},
+	}
+	return ni
+}
+
+func (ni NestedObjectInsert[R]) marshalValue(vs *queryVarSet) string {
+	data := "{}"
+	if len(ni.rows) > 0 {
+		data = fmt.Sprintf("{%s}", ni.rows[0].marshalGQL(vs))
+	}
+	return ni.marshalData(vs, data)
+}
+
+func (ni NestedArrayInsert[R]) OnConflict(constraint Constraint[R], fields ...FieldName[R]) NestedArrayInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: fields,
+	}
+	return ni
+}
+
+// OnConflictWhere updates the fields of the conflicting rows only if they match w.
+func (ni NestedArrayInsert[R]) OnConflictWhere(constraint Constraint[R], w *WhereExpr, fields ...FieldName[R]) NestedArrayInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: fields,
+		where:         w,
+	}
+	return ni
+}
+
+// OnConflictIgnore leaves the conflicting rows as they are.
+func (ni NestedArrayInsert[R]) OnConflictIgnore(constraint Constraint[R]) NestedArrayInsert[R] {
+	ni.onConflict = &onConflict[R]{
+		constraint:    constraint,
+		updateColumns: FieldNameArray[R]{},
+	}
+	return ni
+}
+
+func (ni NestedArrayInsert[R]) marshalValue(vs *queryVarSet) string {
+	stringArr := make([]string, 0, len(ni.rows))
+	for _, row := range ni.rows {
+		stringArr = append(stringArr, fmt.Sprintf("{%s}", row.marshalGQL(vs)))
+	}
+	return ni.marshalData(vs, fmt.Sprintf("[%s]", strings.Join(stringArr, ", ")))
+}
+
+func (ni nestedInsert[R]) marshalData(vs *queryVarSet, data string) string {
+	if ni.onConflict != nil {
+		return fmt.Sprintf("{data: %s, %s}", data, ni.onConflict.marshalGQL(vs))
+	}
+	return fmt.Sprintf("{data: %s}", data)
+}
+
+func Insert[M Model, MP ModelPtr[M]](row FieldArray[M], rows ...FieldArray[M]) InsertQueryBuilder[M] {
+	arr := append([]FieldArray[M]{row}, rows...)
+	return InsertQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				objects: &objects[M]{arr},
+			},
+		},
+	}
+}
+
+type InsertQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (iq InsertQueryBuilder[M]) OnConflict(constraint Constraint[M], fields ...FieldName[M]) InsertQueryBuilder[M] {
+	iq.QuerySkeleton.queryArgs.onConflict = &onConflict[M]{
+		constraint:    constraint,
+		updateColumns: fields,
+	}
+	return iq
+}
+
+// OnConflictWhere updates the fields of the conflicting rows only if they match w.
+func (iq InsertQueryBuilder[M]) OnConflictWhere(constraint Constraint[M], w *WhereExpr, fields ...FieldName[M]) InsertQueryBuilder[M] {
+	iq.QuerySkeleton.queryArgs.onConflict = &onConflict[M]{
+		constraint:    constraint,
+		updateColumns: fields,
+		where:         w,
+	}
+	return iq
+}
+
+// OnConflictIgnore leaves the conflicting rows as they are.
+func (iq InsertQueryBuilder[M]) OnConflictIgnore(constraint Constraint[M]) InsertQueryBuilder[M] {
+	iq.QuerySkeleton.queryArgs.onConflict = &onConflict[M]{
+		constraint:    constraint,
+		updateColumns: FieldNameArray[M]{},
+	}
+	return iq
+}
+
+func (iq *InsertQueryBuilder[M]) MarshalGQL() string {
+	return iq.marshalGQL(nil)
+}
+
+func (iq *InsertQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"insert_%s",
+		iq.QuerySkeleton.marshalGQL(vs),
+	)
+}
+
+func (iq InsertQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) InsertQuery[M] {
+	return InsertQuery[M]{
+		iq:     &iq,
+		fields: append(fields, field),
+	}
+}
+
+type InsertQuery[M Model] struct {
+	iq     *InsertQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (iq InsertQuery[M]) MarshalGQL() string {
+	return iq.marshalGQL(nil)
+}
+
+func (iq InsertQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\naffected_rows\nreturning {\n%s\n}\n}",
+		iq.iq.marshalGQL(vs),
+		selectionArray[M](iq.fields).marshalGQL(vs),
+	)
+}
+
+func (iq InsertQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := iq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation insert_%s%s {\n%s\n}",
+		iq.iq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (iq InsertQuery[M]) Query() string {
+	return operationQuery(iq)
+}
+
+func (iq InsertQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(iq)
+}
+
+func (iq InsertQuery[M]) Exec(client *Client) (*MutationResponse[M], error) {
+	return iq.ExecWithContext(context.Background(), client)
+}
+
+func (iq InsertQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*MutationResponse[M], error) {
+	return execQuery[M, *MutationResponse[M]](ctx, client, iq, fmt.Sprintf("insert_%s", iq.iq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of MutationResponse, eg. into a
+// struct with fields for aliased fields.
+func (iq InsertQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return iq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (iq InsertQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, iq, iq.mutationRoot(), dst)
+}
+
+func (iq InsertQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("insert_%s", iq.iq.ModelName)
+}
+
+func (iq InsertQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (iq InsertQuery[M]) decodeMutationResult(data json.RawMessage) (*MutationResponse[M], error) {
+	return decodeResult[*MutationResponse[M]](data)
+}
@@ -32,15 +32,38 @@ func (iq InsertOneQueryBuilder[M]) OnConflict(constraint Constraint[M], fields .
 	return iq
 }
 
+// OnConflictWhere updates the fields of the conflicting rows only if they match w.
+func (iq InsertOneQueryBuilder[M]) OnConflictWhere(constraint Constraint[M], w *WhereExpr, fields ...FieldName[M]) InsertOneQueryBuilder[M] {
+	iq.QuerySkeleton.queryArgs.onConflict = &onConflict[M]{
+		constraint:    constraint,
+		updateColumns: fields,
+		where:         w,
+	}
+	return iq
+}
+
+// OnConflictIgnore leaves the conflicting rows as they are.
+func (iq InsertOneQueryBuilder[M]) OnConflictIgnore(constraint Constraint[M]) InsertOneQueryBuilder[M] {
+	iq.QuerySkeleton.queryArgs.onConflict = &onConflict[M]{
+		constraint:    constraint,
+		updateColumns: FieldNameArray[M]{},
+	}
+	return iq
+}
+
 func (iq *InsertOneQueryBuilder[M]) MarshalGQL() string {
+	return iq.marshalGQL(nil)
+}
+
+func (iq *InsertOneQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
 	return fmt.Sprintf(
 		"insert_%s_one%s",
 		iq.QuerySkeleton.ModelName,
-		iq.queryArgs.MarshalGQL(),
+		iq.queryArgs.marshalGQL(vs),
 	)
 }
 
-func (iq InsertOneQueryBuilder[M]) Select(field FieldName[M], fields ...FieldName[M]) InsertOneQuery[M] {
+func (iq InsertOneQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) InsertOneQuery[M] {
 	return InsertOneQuery[M]{
 		iq:     &iq,
 		fields: append(fields, field),
@@ -49,53 +72,64 @@ func (iq InsertOneQueryBuilder[M]) Select(field FieldName[M], fields ...FieldNam
 
 type InsertOneQuery[M Model] struct {
 	iq     *InsertOneQueryBuilder[M]
-	fields []FieldName[M]
+	fields []Selection[M]
 }
 
 func (iq InsertOneQuery[M]) MarshalGQL() string {
+	return iq.marshalGQL(nil)
+}
+
+func (iq InsertOneQuery[M]) marshalGQL(vs *queryVarSet) string {
 	return fmt.Sprintf(
 		"%s {\n%s\n}",
-		iq.iq.MarshalGQL(),
-		FieldNameArray[M](iq.fields).MarshalGQL(),
+		iq.iq.marshalGQL(vs),
+		selectionArray[M](iq.fields).marshalGQL(vs),
 	)
 }
 
-func (iq InsertOneQuery[M]) Query() string {
+func (iq InsertOneQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := iq.marshalGQL(vs)
 	return fmt.Sprintf(
 		"mutation insert_%s_one%s {\n%s\n}",
 		iq.iq.ModelName,
-		iq.iq.queryVars.MarshalGQL(),
-		iq.MarshalGQL(),
+		vs.MarshalGQL(),
+		body,
 	)
 }
 
+func (iq InsertOneQuery[M]) Query() string {
+	return operationQuery(iq)
+}
+
 func (iq InsertOneQuery[M]) Variables() map[string]interface{} {
-	vars := map[string]interface{}{}
-	for _, var_ := range iq.iq.queryVars {
-		vars[var_.name] = var_.value.Value()
-	}
-	return vars
+	return operationVariables(iq)
 }
 
 func (iq InsertOneQuery[M]) Exec(client *Client) (*M, error) {
 	return iq.ExecWithContext(context.Background(), client)
 }
 func (iq InsertOneQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*M, error) {
-	respBytes, err := client.Do(ctx, iq)
-	if err != nil {
-		return nil, err
-	}
+	return execQuery[M, *M](ctx, client, iq, fmt.Sprintf("insert_%s_one", iq.iq.ModelName))
+}
 
-	type graphqlResponse struct {
-		Data   map[string]*M  `json:"data"`
-		Errors []GraphQLError `json:"errors"`
-	}
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields.
+func (iq InsertOneQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return iq.ExecIntoWithContext(context.Background(), client, dst)
+}
 
-	respObj := graphqlResponse{}
+func (iq InsertOneQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, iq, iq.mutationRoot(), dst)
+}
 
-	err = json.NewDecoder(respBytes).Decode(&respObj)
-	if err != nil {
-		return nil, err
-	}
-	return respObj.Data[fmt.Sprintf("insert_%s_one", iq.iq.ModelName)], nil
+func (iq InsertOneQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("insert_%s_one", iq.iq.ModelName)
+}
+
+func (iq InsertOneQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (iq InsertOneQuery[M]) decodeMutationResult(data json.RawMessage) (*M, error) {
+	return decodeResult[*M](data)
 }
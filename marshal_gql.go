@@ -1,5 +1,14 @@
 package eywa
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	re "regexp"
+	"sort"
+	"strings"
+)
+
 type GQLMarshaler interface {
 	MarshalGQL() string
 }
@@ -9,3 +18,58 @@ type Enum[T ~string] string
 func (e Enum[T]) MarshalGQL() string {
 	return string(e)
 }
+
+var gqlNamePattern = re.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// marshalGQLLiteral marshals val to a graphql literal through its json
+// encoding, eg. {"a": [1, "b"]} to {a: [1, "b"]}. It fails if val can't be
+// encoded as json, or has an object key which isn't a valid graphql name.
+func marshalGQLLiteral(val interface{}) (string, bool) {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return "", false
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var decoded interface{}
+	if err := d.Decode(&decoded); err != nil {
+		return "", false
+	}
+	return gqlLiteral(decoded)
+}
+
+func gqlLiteral(val interface{}) (string, bool) {
+	switch val := val.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			if !gqlNamePattern.MatchString(k) {
+				return "", false
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		stringArr := make([]string, 0, len(keys))
+		for _, k := range keys {
+			v, ok := gqlLiteral(val[k])
+			if !ok {
+				return "", false
+			}
+			stringArr = append(stringArr, fmt.Sprintf("%s: %s", k, v))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(stringArr, ", ")), true
+	case []interface{}:
+		stringArr := make([]string, 0, len(val))
+		for _, elem := range val {
+			v, ok := gqlLiteral(elem)
+			if !ok {
+				return "", false
+			}
+			stringArr = append(stringArr, v)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(stringArr, ", ")), true
+	default:
+		b, _ := json.Marshal(val)
+		return string(b), true
+	}
+}
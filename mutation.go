@@ -0,0 +1,146 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Mutable is implemented by the mutation queries built with eywa, which can be
+// run in a Mutation with Into: InsertQuery, InsertOneQuery, UpdateQuery,
+// UpdateByPkQuery, UpdateManyQuery, DeleteQuery and DeleteByPkQuery. T is the
+// type returned by their Exec. It can't be implemented outside of eywa.
+type Mutable[T any] interface {
+	marshalGQL(vs *queryVarSet) string
+	mutationRoot() string
+	classifyError(e GraphQLError) error
+	decodeMutationResult(data json.RawMessage) (T, error)
+}
+
+// MutationField is a mutation query run as part of a Mutation, created with
+// Into.
+type MutationField interface {
+	rootField
+	mutationField()
+}
+
+// Into adds the mutation query q to a Mutation, decoding its result into dst,
+// which has the type returned by the Exec of q, eg.
+//
+//	var debited []Account
+//	var credited []Account
+//	err := Mutation(
+//		Into(debit, &debited),
+//		Into(credit, &credited),
+//	).Exec(client)
+func Into[T any](q Mutable[T], dst *T) *MutationTarget[T] {
+	return &MutationTarget[T]{q: q, dst: dst}
+}
+
+// MutationTarget is a mutation query of a Mutation along with the value its
+// result is decoded into.
+type MutationTarget[T any] struct {
+	q     Mutable[T]
+	dst   *T
+	alias string
+	raw   interface{}
+}
+
+// As sets the alias of the root field of the query in the mutation, instead of
+// the generated one. It panics if alias isn't a valid graphql name.
+func (mf *MutationTarget[T]) As(alias string) *MutationTarget[T] {
+	mf.alias = validAlias(alias)
+	return mf
+}
+
+// DecodeInto decodes the result of the query into dst, instead of the value
+// passed to Into, like the ExecInto of the query, eg.
+//
+//	Into(Update[Account]().Set(...).Select(As("cents", Account_Balance)), nil).DecodeInto(&balances)
+func (mf *MutationTarget[T]) DecodeInto(dst interface{}) *MutationTarget[T] {
+	mf.raw = dst
+	return mf
+}
+
+func (mf *MutationTarget[T]) mutationField() {}
+
+func (mf *MutationTarget[T]) userAlias() string {
+	return mf.alias
+}
+
+func (mf *MutationTarget[T]) marshalRootField(vs *queryVarSet, alias string) string {
+	return fmt.Sprintf("%s: %s", alias, mf.q.marshalGQL(vs))
+}
+
+func (mf *MutationTarget[T]) rootName() string {
+	return mf.q.mutationRoot()
+}
+
+func (mf *MutationTarget[T]) classifyError(e GraphQLError) error {
+	return mf.q.classifyError(e)
+}
+
+func (mf *MutationTarget[T]) decode(data json.RawMessage) error {
+	if mf.raw != nil {
+		return decodeInto(data, mf.raw)
+	}
+	result, err := mf.q.decodeMutationResult(data)
+	if err != nil || mf.dst == nil {
+		return err
+	}
+	*mf.dst = result
+	return nil
+}
+
+// Mutation runs the mutation queries as the root fields of a single mutation
+// operation, which hasura runs in one transaction: either all of them succeed,
+// or none of them is applied. Every field is given a unique alias, made of its
+// root field and position, eg. update_account_1, unless one is set with As. It
+// panics if two fields have the same alias.
+func Mutation(field MutationField, fields ...MutationField) MutationQuery {
+	rfs := rootFields{field}
+	for _, f := range fields {
+		rfs = append(rfs, f)
+	}
+	rfs.checkAliases()
+	return MutationQuery{rfs}
+}
+
+type MutationQuery struct {
+	fields rootFields
+}
+
+func (mq MutationQuery) MarshalGQL() string {
+	return mq.marshalGQL(nil)
+}
+
+func (mq MutationQuery) marshalGQL(vs *queryVarSet) string {
+	return mq.fields.marshalGQL(vs)
+}
+
+func (mq MutationQuery) marshalOperation(vs *queryVarSet) string {
+	body := mq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation atomic%s {\n%s\n}",
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (mq MutationQuery) Query() string {
+	return operationQuery(mq)
+}
+
+func (mq MutationQuery) Variables() map[string]interface{} {
+	return operationVariables(mq)
+}
+
+// Exec runs the mutation and decodes the result of every field into the value
+// passed to Into. Nothing is decoded if the mutation fails.
+func (mq MutationQuery) Exec(client *Client) error {
+	return mq.ExecWithContext(context.Background(), client)
+}
+
+func (mq MutationQuery) ExecWithContext(ctx context.Context, client *Client) error {
+	return mq.fields.exec(ctx, client, mq)
+}
@@ -13,32 +13,109 @@ type queryArgs[M Model] struct {
 	where      *where
 	orderBy    *orderBy
 	set        *set[M]
+	inc        *updateOperator[M]
+	append     *updateOperator[M]
+	prepend    *updateOperator[M]
+	deleteKey  *updateOperator[M]
+	deleteElem *updateOperator[M]
+	deletePath *updateOperator[M]
 	object     *object[M]
+	objects    *objects[M]
 	onConflict *onConflict[M]
+	pk         *primaryKey[M]
+	pkColumns  *pkColumns[M]
+	updates    *updates[M]
+	batchSize  *batchSize
+	cursor     *streamCursor[M]
 }
 
-func (qa queryArgs[M]) MarshalGQL() string {
-	var args []string
-	args = appendArg(args, qa.limit)
-	args = appendArg(args, qa.offset)
-	args = appendArg(args, qa.distinctOn)
-	args = appendArg(args, qa.where)
-	args = appendArg(args, qa.orderBy)
-	args = appendArg(args, qa.set)
-	args = appendArg(args, qa.object)
-	args = appendArg(args, qa.onConflict)
-
+func (qa queryArgs[M]) marshalGQL(vs *queryVarSet) string {
+	args := qa.marshalArgs(vs)
 	if len(args) == 0 {
 		return ""
 	}
 	return fmt.Sprintf("(%s)", strings.Join(args, ", "))
 }
 
-func appendArg(arr []string, arg queryArg) []string {
+func (qa queryArgs[M]) marshalArgs(vs *queryVarSet) []string {
+	var args []string
+	args = appendArg(args, vs, qa.limit)
+	args = appendArg(args, vs, qa.offset)
+	args = appendArg(args, vs, qa.distinctOn)
+	args = appendArg(args, vs, qa.where)
+	args = appendArg(args, vs, qa.orderBy)
+	args = appendArg(args, vs, qa.set)
+	args = appendArg(args, vs, qa.inc)
+	args = appendArg(args, vs, qa.append)
+	args = appendArg(args, vs, qa.prepend)
+	args = appendArg(args, vs, qa.deleteKey)
+	args = appendArg(args, vs, qa.deleteElem)
+	args = appendArg(args, vs, qa.deletePath)
+	args = appendArg(args, vs, qa.object)
+	args = appendArg(args, vs, qa.objects)
+	args = appendArg(args, vs, qa.onConflict)
+	args = appendArg(args, vs, qa.pk)
+	args = appendArg(args, vs, qa.pkColumns)
+	args = appendArg(args, vs, qa.updates)
+	args = appendArg(args, vs, qa.batchSize)
+	args = appendArg(args, vs, qa.cursor)
+	return args
+}
+
+// RelationshipArgs are the arguments of a nested array relationship
+// selection, passed to the eywagen generated <Model>_<relationship>With
+// selectors, eg.
+//
+//	User_OrdersWith(
+//		eywa.RelArgs[Order]().OrderBy(eywa.Desc(Order_CreatedAt)).Limit(5),
+//		Order_ID,
+//	)
+//
+// The variables of the arguments are declared in the operation selecting the
+// relationship, like the ones of its own arguments.
+type RelationshipArgs[M Model] struct {
+	queryArgs[M]
+}
+
+func RelArgs[M Model]() RelationshipArgs[M] {
+	return RelationshipArgs[M]{}
+}
+
+func (ra RelationshipArgs[M]) DistinctOn(f FieldName[M], fs ...FieldName[M]) RelationshipArgs[M] {
+	ra.distinctOn = &distinctOn[M]{append([]FieldName[M]{f}, fs...)}
+	return ra
+}
+
+func (ra RelationshipArgs[M]) Offset(n int) RelationshipArgs[M] {
+	ra.offset = (*offset)(&n)
+	return ra
+}
+
+func (ra RelationshipArgs[M]) Limit(n int) RelationshipArgs[M] {
+	ra.limit = (*limit)(&n)
+	return ra
+}
+
+func (ra RelationshipArgs[M]) OrderBy(o ...OrderByExpr) RelationshipArgs[M] {
+	orderByArr := orderBy(o)
+	ra.orderBy = &orderByArr
+	return ra
+}
+
+func (ra RelationshipArgs[M]) Where(w *WhereExpr) RelationshipArgs[M] {
+	ra.where = &where{w}
+	return ra
+}
+
+func (ra RelationshipArgs[M]) MarshalGQL() string {
+	return ra.queryArgs.marshalGQL(nil)
+}
+
+func appendArg(arr []string, vs *queryVarSet, arg queryArg) []string {
 	if arg == nil || reflect.ValueOf(arg).IsNil() {
 		return arr
 	}
-	s := arg.MarshalGQL()
+	s := arg.marshalGQL(vs)
 	if s == "" {
 		return arr
 	}
@@ -47,7 +124,7 @@ func appendArg(arr []string, arg queryArg) []string {
 
 type queryArg interface {
 	queryArgName() string
-	MarshalGQL() string
+	marshalGQL(vs *queryVarSet) string
 }
 
 type limit int
@@ -55,7 +132,10 @@ type limit int
 func (l limit) queryArgName() string {
 	return "limit"
 }
-func (l limit) MarshalGQL() string {
+func (l limit) marshalGQL(vs *queryVarSet) string {
+	if vs.parameterizing() {
+		return fmt.Sprintf("%s: %s", l.queryArgName(), vs.add(queryVar{l.queryArgName(), IntVar(int(l))}))
+	}
 	return fmt.Sprintf("%s: %d", l.queryArgName(), l)
 }
 
@@ -64,19 +144,59 @@ type offset int
 func (o offset) queryArgName() string {
 	return "offset"
 }
-func (o offset) MarshalGQL() string {
+func (o offset) marshalGQL(vs *queryVarSet) string {
+	if vs.parameterizing() {
+		return fmt.Sprintf("%s: %s", o.queryArgName(), vs.add(queryVar{o.queryArgName(), IntVar(int(o))}))
+	}
 	return fmt.Sprintf("%s: %d", o.queryArgName(), o)
 }
 
+type batchSize int
+
+func (bs batchSize) queryArgName() string {
+	return "batch_size"
+}
+func (bs batchSize) marshalGQL(vs *queryVarSet) string {
+	if vs.parameterizing() {
+		return fmt.Sprintf("%s: %s", bs.queryArgName(), vs.add(queryVar{bs.queryArgName(), IntVar(int(bs))}))
+	}
+	return fmt.Sprintf("%s: %d", bs.queryArgName(), bs)
+}
+
+type streamCursor[M Model] struct {
+	field Field[M]
+	desc  bool
+}
+
+func (sc streamCursor[M]) queryArgName() string {
+	return "cursor"
+}
+func (sc streamCursor[M]) marshalGQL(vs *queryVarSet) string {
+	ordering := "ASC"
+	if sc.desc {
+		ordering = "DESC"
+	}
+	return fmt.Sprintf(
+		"%s: {initial_value: {%s: %s}, ordering: %s}",
+		sc.queryArgName(),
+		sc.field.GetName(),
+		sc.field.marshalValue(vs),
+		ordering,
+	)
+}
+
 type distinctOn[M Model] struct {
-	field FieldName[M]
+	fields []FieldName[M]
 }
 
 func (do distinctOn[M]) queryArgName() string {
 	return "distinct_on"
 }
-func (do distinctOn[M]) MarshalGQL() string {
-	return fmt.Sprintf("%s: %s", do.queryArgName(), do.field)
+func (do distinctOn[M]) marshalGQL(vs *queryVarSet) string {
+	if len(do.fields) == 1 {
+		return fmt.Sprintf("%s: %s", do.queryArgName(), do.fields[0])
+	}
+	return fmt.Sprintf("%s: [%s]", do.queryArgName(), joinFieldNames(do.fields))
 }
 
 type where struct {
@@ -86,8 +206,8 @@ type where struct {
 func (w where) queryArgName() string {
 	return "where"
 }
-func (w where) MarshalGQL() string {
-	return fmt.Sprintf("%s: %s", w.queryArgName(), w.WhereExpr.MarshalGQL())
+func (w where) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf("%s: %s", w.queryArgName(), w.WhereExpr.marshalGQL(vs))
 }
 
 type set[M Model] struct {
@@ -97,11 +217,84 @@ type set[M Model] struct {
 func (s set[M]) queryArgName() string {
 	return "_set"
 }
-func (s set[M]) MarshalGQL() string {
+func (s set[M]) marshalGQL(vs *queryVarSet) string {
 	if len(s.FieldArray) == 0 {
 		return ""
 	}
-	return fmt.Sprintf("%s: {%s}", s.queryArgName(), s.FieldArray.MarshalGQL())
+	return fmt.Sprintf("%s: {%s}", s.queryArgName(), s.FieldArray.marshalGQL(vs))
+}
+
+// updateOperator is an update mutation operator other than _set, like _inc or
+// _append.
+type updateOperator[M Model] struct {
+	name   string
+	fields FieldArray[M]
+}
+
+func (uo updateOperator[M]) queryArgName() string {
+	return uo.name
+}
+
+func (uo updateOperator[M]) marshalGQL(vs *queryVarSet) string {
+	if len(uo.fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s: {%s}", uo.queryArgName(), uo.fields.marshalGQL(vs))
+}
+
+func newUpdateOperator[M Model](name string, fields []Field[M]) *updateOperator[M] {
+	return (*updateOperator[M])(nil).add(name, fields...)
+}
+
+// add returns a copy of uo, with fields added to it. A field which is already
+// in it is replaced, as an operator updates every field once.
+func (uo *updateOperator[M]) add(name string, fields ...Field[M]) *updateOperator[M] {
+	added := &updateOperator[M]{name: name}
+	if uo != nil {
+		added.fields = append(added.fields, uo.fields...)
+	}
+	for _, f := range fields {
+		replaced := false
+		for i, existing := range added.fields {
+			if existing.Name == f.Name {
+				added.fields[i] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			added.fields = append(added.fields, f)
+		}
+	}
+	return added
+}
+
+// jsonbFields marshals the values of fields as jsonb literals.
+func jsonbFields[M Model](fields []Field[M]) FieldArray[M] {
+	arr := make(FieldArray[M], 0, len(fields))
+	for _, f := range fields {
+		arr = append(arr, Field[M]{
+			Name:  f.Name,
+			Value: jsonbValue[M]{f},
+		})
+	}
+	return arr
+}
+
+type updates[M Model] struct {
+	entries []queryArgs[M]
+}
+
+func (u updates[M]) queryArgName() string {
+	return "updates"
+}
+
+func (u updates[M]) marshalGQL(vs *queryVarSet) string {
+	stringArr := make([]string, 0, len(u.entries))
+	for _, entry := range u.entries {
+		stringArr = append(stringArr, fmt.Sprintf("{%s}", strings.Join(entry.marshalArgs(vs), ", ")))
+	}
+	return fmt.Sprintf("%s: [%s]", u.queryArgName(), strings.Join(stringArr, ", "))
 }
 
 type operator string
@@ -113,12 +306,63 @@ const (
 	gte operator = "_gte"
 	lt  operator = "_lt"
 	lte operator = "_lte"
+
+	in       operator = "_in"
+	nin      operator = "_nin"
+	isNull   operator = "_is_null"
+	like     operator = "_like"
+	nlike    operator = "_nlike"
+	ilike    operator = "_ilike"
+	nilike   operator = "_nilike"
+	similar  operator = "_similar"
+	nsimilar operator = "_nsimilar"
+	regex    operator = "_regex"
+	nregex   operator = "_nregex"
+	iregex   operator = "_iregex"
+	niregex  operator = "_niregex"
+
+	contains    operator = "_contains"
+	containedIn operator = "_contained_in"
+	hasKey      operator = "_has_key"
+	hasKeysAny  operator = "_has_keys_any"
+	hasKeysAll  operator = "_has_keys_all"
 )
 
 func compare[M Model](oprtr operator, field Field[M]) *WhereExpr {
+	if field.Type != "" && (oprtr == in || oprtr == nin) {
+		field.Type = fmt.Sprintf("[%s!]", field.Type)
+	}
+	// a nil list is an empty list for the list operators, rather than null
+	if v := reflect.ValueOf(field.Value); oprtr.takesList() && v.Kind() == reflect.Slice && v.IsNil() {
+		field.Value = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
 	return &WhereExpr{
-		cmp: fmt.Sprintf("%s: {%s: %s}", field.GetName(), oprtr, field.GetValue()),
+		cmp: &comparison{
+			field:    field.GetName(),
+			operator: oprtr,
+			value:    field,
+		},
+	}
+}
+
+// takesList reports whether the operator compares with a list of values.
+func (o operator) takesList() bool {
+	return o == in || o == nin || o == hasKeysAny || o == hasKeysAll
+}
+
+type comparison struct {
+	field    string
+	operator operator
+	value    valueMarshaler
+	// cast is the type the field is cast to before comparing, if set.
+	cast string
+}
+
+func (c *comparison) marshalGQL(vs *queryVarSet) string {
+	if c.cast != "" {
+		return fmt.Sprintf("%s: {_cast: {%s: {%s: %s}}}", c.field, c.cast, c.operator, c.value.marshalValue(vs))
 	}
+	return fmt.Sprintf("%s: {%s: %s}", c.field, c.operator, c.value.marshalValue(vs))
 }
 
 func Eq[M Model](field Field[M]) *WhereExpr {
@@ -145,6 +389,186 @@ func Lte[M Model](field Field[M]) *WhereExpr {
 	return compare[M](lte, field)
 }
 
+// In matches rows where the field has any of the values in the list held by
+// field.Value, which can be a slice or a list variable created with ListVar.
+func In[M Model](field Field[M]) *WhereExpr {
+	return compare[M](in, field)
+}
+
+// Nin matches rows where the field has none of the values in the list held by
+// field.Value, which can be a slice or a list variable created with ListVar.
+func Nin[M Model](field Field[M]) *WhereExpr {
+	return compare[M](nin, field)
+}
+
+// IsNull matches rows where the field is null if null is true, and rows where
+// it isn't otherwise.
+func IsNull[M Model](field FieldName[M], null bool) *WhereExpr {
+	return compare[M](isNull, Field[M]{
+		Name:  string(field),
+		Value: null,
+		Type:  "Boolean",
+	})
+}
+
+func Like[M Model](field Field[M]) *WhereExpr {
+	return compare[M](like, field)
+}
+
+func Nlike[M Model](field Field[M]) *WhereExpr {
+	return compare[M](nlike, field)
+}
+
+func Ilike[M Model](field Field[M]) *WhereExpr {
+	return compare[M](ilike, field)
+}
+
+func Nilike[M Model](field Field[M]) *WhereExpr {
+	return compare[M](nilike, field)
+}
+
+func Similar[M Model](field Field[M]) *WhereExpr {
+	return compare[M](similar, field)
+}
+
+func Nsimilar[M Model](field Field[M]) *WhereExpr {
+	return compare[M](nsimilar, field)
+}
+
+func Regex[M Model](field Field[M]) *WhereExpr {
+	return compare[M](regex, field)
+}
+
+func Nregex[M Model](field Field[M]) *WhereExpr {
+	return compare[M](nregex, field)
+}
+
+func Iregex[M Model](field Field[M]) *WhereExpr {
+	return compare[M](iregex, field)
+}
+
+func Niregex[M Model](field Field[M]) *WhereExpr {
+	return compare[M](niregex, field)
+}
+
+func compareJSONB[M Model](oprtr operator, field Field[M]) *WhereExpr {
+	return &WhereExpr{
+		cmp: &comparison{
+			field:    field.GetName(),
+			operator: oprtr,
+			value:    jsonbValue[M]{field},
+		},
+	}
+}
+
+// jsonbValue marshals the value of a field as a graphql object literal, instead
+// of a json encoded string.
+type jsonbValue[M Model] struct {
+	Field[M]
+}
+
+// withType returns jv with the Type of its field set to typ.
+func (jv jsonbValue[M]) withType(typ string) valueMarshaler {
+	jv.Type = typ
+	return jv
+}
+
+func (jv jsonbValue[M]) marshalValue(vs *queryVarSet) string {
+	if var_, ok := jv.Value.(queryVar); ok {
+		return vs.add(var_)
+	}
+	val := jv.Value
+	if tv, ok := val.(TypedValue); ok {
+		val = tv.Value()
+	}
+	if !vs.parameterizing() {
+		if literal, ok := marshalGQLLiteral(val); ok {
+			return literal
+		}
+	}
+	// values with object keys which aren't valid graphql names can only be
+	// sent as variables.
+	return vs.add(queryVar{jv.Name, JSONBVar(val)})
+}
+
+// Contains matches rows where the jsonb field contains field.Value.
+func Contains[M Model](field Field[M]) *WhereExpr {
+	return compareJSONB[M](contains, field)
+}
+
+// ContainedIn matches rows where the jsonb field is contained in field.Value.
+func ContainedIn[M Model](field Field[M]) *WhereExpr {
+	return compareJSONB[M](containedIn, field)
+}
+
+// HasKey matches rows where the jsonb field has the top level key.
+func HasKey[M Model](field FieldName[M], key string) *WhereExpr {
+	return compare[M](hasKey, Field[M]{
+		Name:  string(field),
+		Value: key,
+		Type:  "String",
+	})
+}
+
+// HasKeysAny matches rows where the jsonb field has any of the top level keys.
+func HasKeysAny[M Model](field FieldName[M], keys ...string) *WhereExpr {
+	return compare[M](hasKeysAny, Field[M]{
+		Name:  string(field),
+		Value: keys,
+		Type:  "[String!]",
+	})
+}
+
+// HasKeysAll matches rows where the jsonb field has all of the top level keys.
+func HasKeysAll[M Model](field FieldName[M], keys ...string) *WhereExpr {
+	return compare[M](hasKeysAll, Field[M]{
+		Name:  string(field),
+		Value: keys,
+		Type:  "[String!]",
+	})
+}
+
+// CastString casts the jsonb field compared by cmp to String, so that it can
+// be compared with string operators, eg.
+//
+//	CastString(Ilike(Field[M]{Name: "metadata", Value: "%abc%"}))
+//
+// cmp must be a single comparison, which can be combined with others with And,
+// Or, Not and Rel after casting. It panics otherwise.
+func CastString(cmp *WhereExpr) *WhereExpr {
+	return cmp.castTo("String")
+}
+
+func (w *WhereExpr) castTo(typ string) *WhereExpr {
+	if w == nil || w.cmp == nil || w.and != nil || w.or != nil || w.not != nil || w.rel != nil {
+		panic(fmt.Sprintf("eywa: only a single comparison can be cast to %s", typ))
+	}
+	cmp := *w.cmp
+	cmp.cast = typ
+	if cmp.operator.takesList() {
+		typ = fmt.Sprintf("[%s!]", typ)
+	}
+	if v, ok := cmp.value.(interface{ withType(string) valueMarshaler }); ok {
+		cmp.value = v.withType(typ)
+	}
+	return &WhereExpr{cmp: &cmp}
+}
+
+// Rel matches rows of M whose related rows of R, through the relationship
+// field, match w, eg.
+//
+//	Rel[User, Order]("orders", Gt[Order](Order_TotalField(100)))
+//
+// eywagen generates a typed wrapper for every relationship field.
+func Rel[M Model, R Model](field string, w *WhereExpr) *WhereExpr {
+	return &WhereExpr{
+		rel: &relationship{
+			field: field,
+			where: w,
+		},
+	}
+}
+
 func Not(w *WhereExpr) *WhereExpr {
 	return &WhereExpr{
 		not: w,
@@ -167,15 +591,31 @@ type WhereExpr struct {
 	and whereArr
 	or  whereArr
 	not *WhereExpr
-	cmp string
+	cmp *comparison
+	rel *relationship
+}
+
+// relationship is a where expression over a related model, nested under the
+// relationship field.
+type relationship struct {
+	field string
+	where *WhereExpr
+}
+
+func (r *relationship) marshalGQL(vs *queryVarSet) string {
+	expr := r.where.marshalGQL(vs)
+	if expr == "" {
+		expr = "{}"
+	}
+	return fmt.Sprintf("%s: %s", r.field, expr)
 }
 
 type whereArr []*WhereExpr
 
-func (wa whereArr) MarshalGQL() string {
+func (wa whereArr) marshalGQL(vs *queryVarSet) string {
 	stringArr := make([]string, 0, len(wa))
 	for _, whereExpr := range wa {
-		expr := whereExpr.MarshalGQL()
+		expr := whereExpr.marshalGQL(vs)
 		if expr != "" {
 			stringArr = append(stringArr, expr)
 		}
@@ -184,6 +624,10 @@ func (wa whereArr) MarshalGQL() string {
 }
 
 func (w *WhereExpr) MarshalGQL() string {
+	return w.marshalGQL(nil)
+}
+
+func (w *WhereExpr) marshalGQL(vs *queryVarSet) string {
 	if w == nil {
 		return ""
 	}
@@ -192,23 +636,26 @@ func (w *WhereExpr) MarshalGQL() string {
 	}
 	var stringArr []string
 
-	andExpr := w.and.MarshalGQL()
+	andExpr := w.and.marshalGQL(vs)
 	if andExpr != "" {
 		stringArr = append(stringArr, fmt.Sprintf("_and: [%s]", andExpr))
 	}
 
-	orExpr := w.or.MarshalGQL()
+	orExpr := w.or.marshalGQL(vs)
 	if orExpr != "" {
 		stringArr = append(stringArr, fmt.Sprintf("_or: [%s]", orExpr))
 	}
 
-	notExpr := w.not.MarshalGQL()
+	notExpr := w.not.marshalGQL(vs)
 	if notExpr != "" {
 		stringArr = append(stringArr, fmt.Sprintf("_not: %s", notExpr))
 	}
 
-	if w.cmp != "" {
-		stringArr = append(stringArr, w.cmp)
+	if w.cmp != nil {
+		stringArr = append(stringArr, w.cmp.marshalGQL(vs))
+	}
+	if w.rel != nil {
+		stringArr = append(stringArr, w.rel.marshalGQL(vs))
 	}
 	expr := fmt.Sprintf("{%s}", strings.Join(stringArr, ", "))
 	return expr
@@ -217,29 +664,86 @@ func (w *WhereExpr) MarshalGQL() string {
 type OrderByExpr struct {
 	order string
 	field string
+	// path holds the relationships, and aggregate functions, the field is
+	// nested under.
+	path []string
 }
 
 func (ob OrderByExpr) MarshalGQL() string {
-	return fmt.Sprintf("%s: %s", ob.field, ob.order)
+	return ob.marshalGQL(nil)
+}
+
+func (ob OrderByExpr) marshalGQL(vs *queryVarSet) string {
+	expr := fmt.Sprintf("%s: %s", ob.field, ob.order)
+	if vs.parameterizing() {
+		order := queryVar{fmt.Sprintf("%s_order", ob.field), scalarValue{"order_by!", ob.order}}
+		expr = fmt.Sprintf("%s: %s", ob.field, vs.add(order))
+	}
+	for i := len(ob.path) - 1; i >= 0; i-- {
+		expr = fmt.Sprintf("%s: {%s}", ob.path[i], expr)
+	}
+	return expr
+}
+
+// key is the top level key of the order_by input object ob is marshalled to.
+func (ob OrderByExpr) key() string {
+	if len(ob.path) > 0 {
+		return ob.path[0]
+	}
+	return ob.field
+}
+
+func (ob OrderByExpr) nest(field string) OrderByExpr {
+	ob.path = append([]string{field}, ob.path...)
+	return ob
+}
+
+// OrderByRel orders rows of M by ob, an order over the object relationship of
+// R through field, eg.
+//
+//	OrderByRel[Book, Author]("author", Asc(Author_Name))
+func OrderByRel[M Model, R Model](field string, ob OrderByExpr) OrderByExpr {
+	return ob.nest(field)
+}
+
+// OrderByAggregate orders rows of M by ob, an order over an aggregate of the
+// array relationship of R through field, eg.
+//
+//	OrderByAggregate[User, Order]("orders", CountOrder(Desc[Order]))
+//	OrderByAggregate[User, Order]("orders", AggregateOrder("max", Desc(Order_Total)))
+func OrderByAggregate[M Model, R Model](field string, ob OrderByExpr) OrderByExpr {
+	return ob.nest(fmt.Sprintf("%s_aggregate", field))
+}
+
+// CountOrder orders by the count of rows of R, in the direction of order, like
+// Asc[R] or Desc[R].
+func CountOrder[R Model](order func(FieldName[R]) OrderByExpr) OrderByExpr {
+	return order("count")
+}
+
+// AggregateOrder orders by the aggregate function fn, like max or sum, of the
+// field ordered by ob.
+func AggregateOrder(fn string, ob OrderByExpr) OrderByExpr {
+	return ob.nest(fn)
 }
 
 func Asc[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"asc", string(field)}
+	return OrderByExpr{order: "asc", field: string(field)}
 }
 func AscNullsFirst[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"asc_nulls_first", string(field)}
+	return OrderByExpr{order: "asc_nulls_first", field: string(field)}
 }
 func AscNullsLast[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"asc_nulls_last", string(field)}
+	return OrderByExpr{order: "asc_nulls_last", field: string(field)}
 }
 func Desc[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"desc", string(field)}
+	return OrderByExpr{order: "desc", field: string(field)}
 }
 func DescNullsFirst[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"desc_nulls_first", string(field)}
+	return OrderByExpr{order: "desc_nulls_first", field: string(field)}
 }
 func DescNullsLast[M Model](field FieldName[M]) OrderByExpr {
-	return OrderByExpr{"desc_nulls_last", string(field)}
+	return OrderByExpr{order: "desc_nulls_last", field: string(field)}
 }
 
 type orderBy []OrderByExpr
@@ -248,16 +752,25 @@ func (oba orderBy) queryArgName() string {
 	return "order_by"
 }
 
-func (oba orderBy) MarshalGQL() string {
+func (oba orderBy) marshalGQL(vs *queryVarSet) string {
 	if len(oba) == 0 {
 		return ""
 	}
 	stringArr := make([]string, 0, len(oba))
+	keys := make(map[string]bool, len(oba))
+	repeated := false
 	for _, ob := range oba {
-		expr := ob.MarshalGQL()
+		expr := ob.marshalGQL(vs)
 		if expr != "" {
 			stringArr = append(stringArr, expr)
 		}
+		repeated = repeated || keys[ob.key()]
+		keys[ob.key()] = true
+	}
+	// an input object can't have a key more than once, eg. when ordering by
+	// more than one field of a relationship.
+	if repeated {
+		return fmt.Sprintf("%s: [{%s}]", oba.queryArgName(), strings.Join(stringArr, "}, {"))
 	}
 	return fmt.Sprintf("%s: {%s}", oba.queryArgName(), strings.Join(stringArr, ", "))
 }
@@ -270,22 +783,69 @@ func (o object[M]) queryArgName() string {
 	return "object"
 }
 
-func (o object[M]) MarshalGQL() string {
-	return fmt.Sprintf("%s: {%s}", o.queryArgName(), o.fields.MarshalGQL())
+func (o object[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf("%s: {%s}", o.queryArgName(), o.fields.marshalGQL(vs))
+}
+
+type objects[M Model] struct {
+	rows []FieldArray[M]
+}
+
+func (o objects[M]) queryArgName() string {
+	return "objects"
+}
+
+func (o objects[M]) marshalGQL(vs *queryVarSet) string {
+	stringArr := make([]string, 0, len(o.rows))
+	for _, row := range o.rows {
+		stringArr = append(stringArr, fmt.Sprintf("{%s}", row.marshalGQL(vs)))
+	}
+	return fmt.Sprintf("%s: [%s]", o.queryArgName(), strings.Join(stringArr, ", "))
 }
 
 type onConflict[M Model] struct {
-	constraint    Constraint[M]
+	constraint Constraint[M]
+	// updateColumns is left out if nil, an empty non nil updateColumns
+	// ignores the conflicting rows.
 	updateColumns FieldNameArray[M]
+	where         *WhereExpr
 }
 
 func (oc onConflict[M]) queryArgName() string {
 	return "on_conflict"
 }
 
-func (oc onConflict[M]) MarshalGQL() string {
-	if oc.updateColumns == nil {
-		return fmt.Sprintf("%s: {constraint: %s}", oc.queryArgName(), string(oc.constraint))
+func (oc onConflict[M]) marshalGQL(vs *queryVarSet) string {
+	stringArr := []string{fmt.Sprintf("constraint: %s", string(oc.constraint))}
+	if oc.updateColumns != nil {
+		stringArr = append(stringArr, fmt.Sprintf("update_columns: [%s]", oc.updateColumns.MarshalGQL()))
+	}
+	if oc.where != nil {
+		stringArr = append(stringArr, fmt.Sprintf("where: %s", oc.where.marshalGQL(vs)))
 	}
-	return fmt.Sprintf("%s: {constraint: %s, update_columns: [%s]}", oc.queryArgName(), string(oc.constraint), oc.updateColumns.MarshalGQL())
+	return fmt.Sprintf("%s: {%s}", oc.queryArgName(), strings.Join(stringArr, ", "))
+}
+
+type primaryKey[M Model] struct {
+	fields FieldArray[M]
+}
+
+func (pk primaryKey[M]) queryArgName() string {
+	return "pk"
+}
+
+func (pk primaryKey[M]) marshalGQL(vs *queryVarSet) string {
+	return pk.fields.marshalGQL(vs)
+}
+
+type pkColumns[M Model] struct {
+	fields FieldArray[M]
+}
+
+func (pc pkColumns[M]) queryArgName() string {
+	return "pk_columns"
+}
+
+func (pc pkColumns[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf("%s: {%s}", pc.queryArgName(), pc.fields.marshalGQL(vs))
 }
@@ -3,6 +3,7 @@ package eywa
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 )
 
 type queryVar struct {
@@ -31,6 +32,151 @@ func (vs queryVarArr) MarshalGQL() string {
 	return buf.String()
 }
 
+// queryVarSet collects the variables used in a query while it is marshalled,
+// to be declared in the operation and sent along with it.
+type queryVarSet struct {
+	vars  queryVarArr
+	names map[string]int
+	// parameterize lifts literal values into variables while marshalling.
+	parameterize bool
+	// fragments are the fragments spread in the selections, to be defined
+	// along with the operation.
+	fragments     []fragment
+	fragmentNames map[string]bool
+	// overridden are the names of the variables given to Fragment.Spread,
+	// which take precedence over the ones of the directives in the fragment.
+	overridden map[string]bool
+}
+
+func (vs *queryVarSet) parameterizing() bool {
+	return vs != nil && vs.parameterize
+}
+
+// add registers the variable in the set and returns the reference to it to be
+// used in the query. A variable with the same name and value as one already in
+// the set reuses it, while one with the same name but a different value is
+// renamed by suffixing a number, eg. testTable_Name_1. While parameterizing,
+// every variable is given its own name, so that the query text doesn't depend
+// on the values. A nil set only returns the reference.
+func (vs *queryVarSet) add(v queryVar) string {
+	if vs == nil {
+		return fmt.Sprintf("$%s", v.name)
+	}
+	if vs.names == nil {
+		vs.names = make(map[string]int)
+	}
+
+	name := v.name
+	for i := 1; ; i++ {
+		idx, ok := vs.names[name]
+		if !ok {
+			break
+		}
+		existing := vs.vars[idx].value
+		if !vs.parameterize && existing.Type() == v.value.Type() && reflect.DeepEqual(existing.Value(), v.value.Value()) {
+			return fmt.Sprintf("$%s", name)
+		}
+		name = fmt.Sprintf("%s_%d", v.name, i)
+	}
+
+	vs.names[name] = len(vs.vars)
+	vs.vars = append(vs.vars, queryVar{name, v.value})
+	return fmt.Sprintf("$%s", name)
+}
+
+// addShared registers the variable in the set like add, but reuses the
+// variable of the same name already in it, so that it's declared once, eg.
+// when it's shared by the directives of several fields. It panics if the
+// variables differ in type or value, unless the variable is overridden.
+func (vs *queryVarSet) addShared(v queryVar) string {
+	if vs == nil {
+		return vs.add(v)
+	}
+	idx, ok := vs.names[v.name]
+	if !ok {
+		return vs.add(v)
+	}
+	existing := vs.vars[idx].value
+	if existing.Type() != v.value.Type() || !vs.overridden[v.name] && !reflect.DeepEqual(existing.Value(), v.value.Value()) {
+		panic(fmt.Sprintf("eywa: conflicting values for variable %s", v.name))
+	}
+	return fmt.Sprintf("$%s", v.name)
+}
+
+// override registers the variable in the set like addShared, to be used by
+// the variables of the same name added with addShared after it whatever their
+// values, see Fragment.Spread.
+func (vs *queryVarSet) override(v queryVar) {
+	if vs == nil {
+		return
+	}
+	vs.addShared(v)
+	if vs.overridden == nil {
+		vs.overridden = make(map[string]bool)
+	}
+	vs.overridden[v.name] = true
+}
+
+func (vs *queryVarSet) MarshalGQL() string {
+	return vs.vars.MarshalGQL()
+}
+
+func (vs *queryVarSet) values() map[string]interface{} {
+	vars := map[string]interface{}{}
+	for _, var_ := range vs.vars {
+		vars[var_.name] = var_.value.Value()
+	}
+	return vars
+}
+
+// operation is implemented by the queries built with eywa. marshalOperation
+// marshals the whole graphql operation, collecting its variables in vs.
+type operation interface {
+	marshalOperation(vs *queryVarSet) string
+}
+
+func operationQuery(op operation) string {
+	vs := &queryVarSet{}
+	return op.marshalOperation(vs) + vs.marshalFragments()
+}
+
+func operationVariables(op operation) map[string]interface{} {
+	vs := &queryVarSet{}
+	op.marshalOperation(vs)
+	return vs.values()
+}
+
+// Parameterize returns q with every literal value of its arguments lifted into
+// a typed graphql variable, so that the query text stays the same irrespective
+// of the values used. The variables are typed with the Type of their Field, as
+// generated by eywagen from the schema. Marshalling the query panics if a
+// literal value can't be lifted, as its Field has no Type, eg. for enum or
+// jsonb fields without a type option in their eywa tag. Queries not built with
+// eywa are returned as is.
+func Parameterize(q Queryable) Queryable {
+	if op, ok := q.(operation); ok {
+		return parameterized{op}
+	}
+	return q
+}
+
+type parameterized struct {
+	op operation
+}
+
+func (p parameterized) marshalOperation(vs *queryVarSet) string {
+	vs.parameterize = true
+	return p.op.marshalOperation(vs)
+}
+
+func (p parameterized) Query() string {
+	return operationQuery(p)
+}
+
+func (p parameterized) Variables() map[string]interface{} {
+	return operationVariables(p)
+}
+
 func QueryVar(name string, value TypedValue) queryVar {
 	return queryVar{name, value}
 }
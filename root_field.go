@@ -0,0 +1,108 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// rootField is a root field of an operation combining several queries, like
+// Mutation or Batch, which decodes its own result.
+type rootField interface {
+	marshalRootField(vs *queryVarSet, alias string) string
+	rootName() string
+	// userAlias is the alias set with As, if any.
+	userAlias() string
+	classifyError(e GraphQLError) error
+	decode(data json.RawMessage) error
+}
+
+func decodeResult[T any](data json.RawMessage) (T, error) {
+	var result T
+	if len(data) == 0 {
+		return result, nil
+	}
+	err := json.Unmarshal(data, &result)
+	return result, err
+}
+
+// decodeInto decodes the result of a field into dst, for the DecodeInto of the
+// fields.
+func decodeInto(data json.RawMessage, dst interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// rootFields gives every field without an alias set with As a unique alias,
+// made of its root field and position, eg. update_account_1.
+type rootFields []rootField
+
+func (rfs rootFields) alias(i int) string {
+	if alias := rfs[i].userAlias(); alias != "" {
+		return alias
+	}
+	return fmt.Sprintf("%s_%d", rfs[i].rootName(), i)
+}
+
+// checkAliases panics if two of the fields have the same alias, including an
+// alias set with As which is the generated alias of another field.
+func (rfs rootFields) checkAliases() {
+	aliases := make(map[string]bool, len(rfs))
+	for i := range rfs {
+		alias := rfs.alias(i)
+		if aliases[alias] {
+			panic(fmt.Sprintf("eywa: duplicate alias %s", alias))
+		}
+		aliases[alias] = true
+	}
+}
+
+func (rfs rootFields) marshalGQL(vs *queryVarSet) string {
+	stringArr := make([]string, 0, len(rfs))
+	for i, f := range rfs {
+		stringArr = append(stringArr, f.marshalRootField(vs, rfs.alias(i)))
+	}
+	return strings.Join(stringArr, "\n")
+}
+
+// classifyError classifies e with the field it was caused by, found through the
+// alias in the path of the error.
+func (rfs rootFields) classifyError(e GraphQLError) error {
+	path := e.Path()
+	for i, f := range rfs {
+		alias := rfs.alias(i)
+		if strings.HasSuffix(path, "."+alias) || strings.Contains(path, "."+alias+".") {
+			return f.classifyError(e)
+		}
+	}
+	return e
+}
+
+// exec runs q, made of the fields, and decodes the result of every field.
+// Nothing is decoded if the response contains errors.
+func (rfs rootFields) exec(ctx context.Context, client *Client, q Queryable) error {
+	respBytes, err := client.Do(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	respObj := graphqlResponse[json.RawMessage]{}
+	err = json.NewDecoder(respBytes).Decode(&respObj)
+	if err != nil {
+		return err
+	}
+
+	if len(respObj.Errors) > 0 {
+		return classifyGraphQLErrors(respObj.Errors, rfs.classifyError)
+	}
+
+	for i, f := range rfs {
+		if err := f.decode(respObj.Data[rfs.alias(i)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
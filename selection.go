@@ -0,0 +1,89 @@
+package eywa
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Selection is a field selected in a query. It's either a FieldName, or one of
+// the selections built from them by the eywagen generated relationship
+// selectors, As, Include, Skip and Fragment.Spread.
+type Selection[M Model] interface {
+	marshalSelection(vs *queryVarSet) string
+	// withDirective returns the selection with the directive added, see
+	// Include.
+	withDirective(d directive) Selection[M]
+	// selectionOf ties the selection to M, so that M can be inferred from it,
+	// eg. in As("name", User_Name).
+	selectionOf(M)
+}
+
+func (f FieldName[M]) marshalSelection(vs *queryVarSet) string {
+	return string(f)
+}
+func (f FieldName[M]) selectionOf(M) {}
+
+type selectionArray[M Model] []Selection[M]
+
+// marshalGQL marshals the selections, collecting the variables of their
+// arguments and directives, and the fragments they spread, in vs.
+func (sa selectionArray[M]) marshalGQL(vs *queryVarSet) string {
+	buf := bytes.NewBufferString("")
+	for i, s := range sa {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(s.marshalSelection(vs))
+	}
+	return buf.String()
+}
+
+// Relationship selects the fields of the related model R through the
+// relationship field of M, with the given arguments. It's used by the eywagen
+// generated <Model>_<relationship> selectors, and the
+// <Model>_<relationship>With ones of array relationships, as object
+// relationships take no arguments. The variables of the arguments are declared
+// in the operation selecting it.
+func Relationship[M Model, R Model](field string, args RelationshipArgs[R], subField Selection[R], subFields ...Selection[R]) Selection[M] {
+	return relationshipSelection[M, R]{
+		field:  field,
+		args:   args,
+		fields: append([]Selection[R]{subField}, subFields...),
+	}
+}
+
+type relationshipSelection[M Model, R Model] struct {
+	field      string
+	args       RelationshipArgs[R]
+	directives directives
+	fields     selectionArray[R]
+}
+
+func (r relationshipSelection[M, R]) selectionOf(M) {}
+func (r relationshipSelection[M, R]) withDirective(d directive) Selection[M] {
+	r.directives = r.directives.with(d)
+	return r
+}
+func (r relationshipSelection[M, R]) marshalSelection(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s%s%s {\n%s\n}",
+		r.field,
+		r.args.queryArgs.marshalGQL(vs),
+		r.directives.marshalGQL(vs),
+		r.fields.marshalGQL(vs),
+	)
+}
+
+type aliasedSelection[M Model] struct {
+	alias string
+	field Selection[M]
+}
+
+func (as aliasedSelection[M]) selectionOf(M) {}
+func (as aliasedSelection[M]) withDirective(d directive) Selection[M] {
+	as.field = as.field.withDirective(d)
+	return as
+}
+func (as aliasedSelection[M]) marshalSelection(vs *queryVarSet) string {
+	return fmt.Sprintf("%s: %s", as.alias, as.field.marshalSelection(vs))
+}
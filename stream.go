@@ -0,0 +1,226 @@
+package eywa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Stream builds a streaming subscription over the rows of M, ordered by the
+// cursor field and starting after its value, eg.
+//
+//	Stream[Event](Event_IDField(lastID)).BatchSize(100).Select(Event_ID, Event_Payload)
+//
+// The batch size, which is a required argument, defaults to
+// DefaultStreamBatchSize.
+func Stream[M Model, MP ModelPtr[M]](cursor Field[M]) StreamQueryBuilder[M] {
+	n := batchSize(DefaultStreamBatchSize)
+	return StreamQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				batchSize: &n,
+				cursor:    &streamCursor[M]{field: cursor},
+			},
+		},
+	}
+}
+
+// DefaultStreamBatchSize is the maximum number of rows in a batch of a
+// streaming subscription, unless set with BatchSize.
+const DefaultStreamBatchSize = 100
+
+type StreamQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (sq StreamQueryBuilder[M]) BatchSize(n int) StreamQueryBuilder[M] {
+	sq.batchSize = (*batchSize)(&n)
+	return sq
+}
+
+// Desc streams the rows in descending order of the cursor field.
+func (sq StreamQueryBuilder[M]) Desc() StreamQueryBuilder[M] {
+	cursor := *sq.cursor
+	cursor.desc = true
+	sq.cursor = &cursor
+	return sq
+}
+
+func (sq StreamQueryBuilder[M]) Where(w *WhereExpr) StreamQueryBuilder[M] {
+	sq.where = &where{w}
+	return sq
+}
+
+func (sq StreamQueryBuilder[M]) MarshalGQL() string {
+	return sq.marshalGQL(nil)
+}
+
+func (sq StreamQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s_stream%s",
+		sq.QuerySkeleton.ModelName,
+		sq.queryArgs.marshalGQL(vs),
+	)
+}
+
+// Select selects the fields of the streamed rows. The cursor field is always
+// selected.
+func (sq StreamQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) StreamQuery[M] {
+	fields = append(fields, field)
+	cursorSelected := false
+	for _, f := range fields {
+		name, ok := f.(FieldName[M])
+		cursorSelected = cursorSelected || ok && string(name) == sq.cursor.field.GetName()
+	}
+	if !cursorSelected {
+		fields = append(fields, FieldName[M](sq.cursor.field.GetName()))
+	}
+	return StreamQuery[M]{
+		sq:     &sq,
+		fields: fields,
+	}
+}
+
+type StreamQuery[M Model] struct {
+	sq     *StreamQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (sq StreamQuery[M]) MarshalGQL() string {
+	return sq.marshalGQL(nil)
+}
+
+func (sq StreamQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\n%s\n}",
+		sq.sq.marshalGQL(vs),
+		selectionArray[M](sq.fields).marshalGQL(vs),
+	)
+}
+
+func (sq StreamQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := sq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"subscription stream_%s%s {\n%s\n}",
+		sq.sq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (sq StreamQuery[M]) Query() string {
+	return operationQuery(sq)
+}
+
+func (sq StreamQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(sq)
+}
+
+// StreamSubscription delivers the batches of rows of a streaming subscription,
+// in the order of the cursor field, on Batches. Errors receives the error
+// which ended the subscription, if any. Both are closed when it ends.
+type StreamSubscription[M Model] struct {
+	Batches <-chan []M
+	Errors  <-chan error
+
+	mu sync.Mutex
+	sq StreamQuery[M]
+}
+
+// Cursor returns the cursor field with the value of the last row sent on
+// Batches, or the initial value if no row was sent yet. It can be passed to
+// Stream to resume the subscription, eg. after a restart.
+func (s *StreamSubscription[M]) Cursor() Field[M] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sq.sq.cursor.field
+}
+
+// advance moves the cursor to the last row of the batch, before it's sent on
+// Batches, so that Cursor includes it as soon as it's received. The returned
+// function moves it back, if the batch isn't received.
+func (s *StreamSubscription[M]) advance(batch []M) func() {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.sq.sq
+	cursor := *previous.cursor
+	value, ok := fieldByJSONName(reflect.ValueOf(batch[len(batch)-1]), cursor.field.GetName())
+	if !ok || !value.CanInterface() {
+		return nil
+	}
+	cursor.field.Value = value.Interface()
+	sq := *previous
+	sq.cursor = &cursor
+	s.sq.sq = &sq
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.sq.sq = previous
+	}
+}
+
+// fieldByJSONName returns the field of the struct v which is encoded as name
+// in json, looking into embedded structs too.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && tag == "" {
+			if f, ok := fieldByJSONName(v.Field(i), name); ok {
+				return f, true
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if tag == name || tag == "" && sf.Name == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func (s *StreamSubscription[M]) marshalOperation(vs *queryVarSet) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sq.marshalOperation(vs)
+}
+
+func (s *StreamSubscription[M]) Query() string {
+	return operationQuery(s)
+}
+
+func (s *StreamSubscription[M]) Variables() map[string]interface{} {
+	return operationVariables(s)
+}
+
+// Subscribe streams the rows after the cursor until ctx is cancelled. If the
+// connection drops, the subscription is resumed from the last row sent. The
+// error ending the subscription, if any, eg. ErrSubscriptionRejected or
+// ErrHandshakeFailed, is sent on Errors. See SubscriptionClient.
+func (sq StreamQuery[M]) Subscribe(ctx context.Context, client *SubscriptionClient) *StreamSubscription[M] {
+	s := &StreamSubscription[M]{sq: sq}
+	s.Batches, s.Errors = subscribe[M, []M](ctx, client, s, fmt.Sprintf("%s_stream", sq.sq.ModelName), s.advance)
+	return s
+}
@@ -0,0 +1,305 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const graphqlTransportWS = "graphql-transport-ws"
+
+// ErrSubscriptionRejected is returned when the server closes a subscription
+// connection with a 44xx code of the graphql-transport-ws protocol, eg. when
+// it is unauthorized. Such subscriptions aren't retried.
+var ErrSubscriptionRejected = errors.New("subscription rejected")
+
+// ErrHandshakeFailed is returned when the websocket handshake of a
+// subscription fails in a way reconnecting won't fix, eg. when the server
+// answers with a 401 or 403, or doesn't speak graphql-transport-ws. Such
+// subscriptions aren't retried, while handshakes failing with a 408, 429 or
+// 5xx status are.
+var ErrHandshakeFailed = errors.New("websocket handshake failed")
+
+// SubscriptionClient runs graphql subscriptions over websockets, using the
+// graphql-transport-ws protocol. Every subscription uses its own connection,
+// which is reopened with an exponential backoff if it drops.
+type SubscriptionClient struct {
+	endpoint         string
+	httpClient       *http.Client
+	headers          map[string]string
+	initPayload      map[string]interface{}
+	pingInterval     time.Duration
+	minBackoff       time.Duration
+	maxBackoff       time.Duration
+	autoParameterize bool
+}
+
+type SubscriptionClientOpts struct {
+	// Headers are sent with the websocket handshake request. Unless
+	// InitPayload is set, they are also sent as {"headers": Headers} in the
+	// connection_init payload, which is where hasura reads them from.
+	Headers     map[string]string
+	InitPayload map[string]interface{}
+	// PingInterval is the interval between the pings sent to the server. The
+	// connection is reopened if nothing is received from the server for two
+	// intervals. It defaults to 30 seconds.
+	PingInterval time.Duration
+	// MinBackoff and MaxBackoff bound the wait before reconnecting, which
+	// doubles after every failed attempt. They default to 500ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// AutoParameterize sends every subscription through Parameterize.
+	AutoParameterize bool
+	// HTTPClient sends the websocket handshake requests, eg. with a custom
+	// TLS config or proxy. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewSubscriptionClient accepts a graphql websocket endpoint, with the ws or
+// wss scheme, and returns back a SubscriptionClient.
+func NewSubscriptionClient(wsEndpoint string, opt *SubscriptionClientOpts) *SubscriptionClient {
+	c := &SubscriptionClient{
+		endpoint:     wsEndpoint,
+		pingInterval: 30 * time.Second,
+		minBackoff:   500 * time.Millisecond,
+		maxBackoff:   30 * time.Second,
+	}
+
+	if opt != nil {
+		if len(opt.Headers) > 0 {
+			c.headers = opt.Headers
+			c.initPayload = map[string]interface{}{"headers": opt.Headers}
+		}
+		if opt.InitPayload != nil {
+			c.initPayload = opt.InitPayload
+		}
+		if opt.PingInterval > 0 {
+			c.pingInterval = opt.PingInterval
+		}
+		if opt.MinBackoff > 0 {
+			c.minBackoff = opt.MinBackoff
+		}
+		if opt.MaxBackoff > 0 {
+			c.maxBackoff = opt.MaxBackoff
+		}
+		if c.maxBackoff < c.minBackoff {
+			c.maxBackoff = c.minBackoff
+		}
+		c.autoParameterize = opt.AutoParameterize
+		c.httpClient = opt.HTTPClient
+	}
+
+	return c
+}
+
+type wsMessage struct {
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+type wsIncomingMessage struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// subscription marshals a query built with eywa as a subscription operation.
+type subscription struct {
+	op operation
+}
+
+func (s subscription) marshalOperation(vs *queryVarSet) string {
+	return fmt.Sprintf("subscription%s", strings.TrimPrefix(s.op.marshalOperation(vs), "query"))
+}
+
+func (s subscription) Query() string {
+	return operationQuery(s)
+}
+
+func (s subscription) Variables() map[string]interface{} {
+	return operationVariables(s)
+}
+
+// subscribe runs q as a subscription and sends the data under root of every
+// result on the returned channel, until ctx is cancelled or the server
+// completes the subscription. Graphql errors, rejected connections
+// (ErrSubscriptionRejected) and handshakes failing for good
+// (ErrHandshakeFailed), eg. with a 401 response, end the subscription with the
+// error sent on the error channel, while other failures are retried. Both
+// channels are closed when the subscription ends. receive, if not nil, is
+// called with every result before it's sent on the channel, and the function
+// it returns, if any, is called if the result isn't received.
+func subscribe[M Model, T any](ctx context.Context, client *SubscriptionClient, q Queryable, root string, receive func(T) func()) (<-chan T, <-chan error) {
+	if client.autoParameterize {
+		q = Parameterize(q)
+	}
+	results := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(results)
+
+		backoff := client.minBackoff
+		for {
+			acked, err := runSubscription[M, T](ctx, client, q, root, results, receive)
+			if ctx.Err() != nil || err == nil {
+				return
+			}
+			var gqlErrs *GraphQLErrors
+			if errors.As(err, &gqlErrs) || errors.Is(err, ErrSubscriptionRejected) || errors.Is(err, ErrHandshakeFailed) {
+				errs <- err
+				return
+			}
+			if acked {
+				backoff = client.minBackoff
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > client.maxBackoff {
+				backoff = client.maxBackoff
+			}
+		}
+	}()
+	return results, errs
+}
+
+// runSubscription runs q on a single connection, until it drops or the
+// subscription ends. acked reports whether the server acknowledged the
+// connection.
+func runSubscription[M Model, T any](ctx context.Context, client *SubscriptionClient, q Queryable, root string, results chan<- T, receive func(T) func()) (acked bool, err error) {
+	conn, err := dialWebSocket(ctx, client.httpClient, client.endpoint, graphqlTransportWS, client.headers)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if closeErr := conn.close(websocket.StatusNormalClosure, ""); err == nil {
+			err = closeErr
+		}
+	}()
+	stop := context.AfterFunc(ctx, func() {
+		// the subscription is over, so the connection is dropped if the
+		// complete message can't be sent, and the close error is moot.
+		writeCtx, cancel := context.WithTimeout(context.Background(), client.pingInterval)
+		defer cancel()
+		if conn.writeJSON(writeCtx, wsMessage{ID: "1", Type: "complete"}) != nil {
+			conn.closeNow()
+			return
+		}
+		conn.close(websocket.StatusNormalClosure, "")
+	})
+	defer stop()
+
+	// reads outlive ctx, which would drop the connection before the complete
+	// message is sent. Closing the connection once ctx is done ends them.
+	readCtx := context.WithoutCancel(ctx)
+	read := func() (*wsIncomingMessage, error) {
+		msg := &wsIncomingMessage{}
+		if err := conn.readJSON(readCtx, 2*client.pingInterval, msg); err != nil {
+			if code, reason, ok := wsCloseStatus(err); ok && code >= 4400 && code < 4500 {
+				return nil, fmt.Errorf("%w: %d %s", ErrSubscriptionRejected, code, reason)
+			}
+			return nil, err
+		}
+		if msg.Type == "ping" {
+			return msg, conn.writeJSON(ctx, wsMessage{Type: "pong"})
+		}
+		return msg, nil
+	}
+
+	if err := conn.writeJSON(ctx, wsMessage{Type: "connection_init", Payload: client.initPayload}); err != nil {
+		return false, err
+	}
+	for !acked {
+		msg, err := read()
+		if err != nil {
+			return false, err
+		}
+		acked = msg.Type == "connection_ack"
+	}
+
+	err = conn.writeJSON(ctx, wsMessage{
+		ID:   "1",
+		Type: "subscribe",
+		Payload: graphqlRequest{
+			Query:     q.Query(),
+			Variables: q.Variables(),
+		},
+	})
+	if err != nil {
+		return acked, err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(client.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// a failed ping drops the connection, failing the read
+				// below, which reconnects, unless ctx is done and the
+				// connection is being closed already.
+				if conn.writeJSON(ctx, wsMessage{Type: "ping"}) != nil {
+					if ctx.Err() == nil {
+						conn.closeNow()
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		msg, err := read()
+		if err != nil {
+			return acked, err
+		}
+		if msg.ID != "1" {
+			continue
+		}
+		switch msg.Type {
+		case "next":
+			resp := graphqlResponse[T]{}
+			if err := json.Unmarshal(msg.Payload, &resp); err != nil {
+				return acked, err
+			}
+			if len(resp.Errors) > 0 {
+				return acked, newGraphQLErrors[M](resp.Errors)
+			}
+			var undo func()
+			if receive != nil {
+				undo = receive(resp.Data[root])
+			}
+			select {
+			case results <- resp.Data[root]:
+			case <-ctx.Done():
+				if undo != nil {
+					undo()
+				}
+				return acked, ctx.Err()
+			}
+		case "error":
+			var gqlErrs []GraphQLError
+			if err := json.Unmarshal(msg.Payload, &gqlErrs); err != nil {
+				return acked, err
+			}
+			return acked, newGraphQLErrors[M](gqlErrs)
+		case "complete":
+			return acked, nil
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+type subTestModel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (m subTestModel) ModelName() string {
+	return "sub_test"
+}
+
+func (m subTestModel) TableName() string {
+	return "sub_test"
+}
+
+const (
+	subTestModel_ID   FieldName[subTestModel] = "id"
+	subTestModel_Name FieldName[subTestModel] = "name"
+)
+
+// wsTestConn is the server side of a test websocket connection.
+type wsTestConn struct {
+	*wsConn
+	ctx context.Context
+}
+
+func (c *wsTestConn) writeJSON(v interface{}) error {
+	return c.wsConn.writeJSON(c.ctx, v)
+}
+
+func (c *wsTestConn) close(code websocket.StatusCode, reason string) error {
+	return c.wsConn.close(code, reason)
+}
+
+// newWSTestServer starts a graphql-transport-ws stand-in, running handle for
+// every websocket connection.
+func newWSTestServer(t *testing.T, handle func(c *wsTestConn, r *http.Request)) *httptest.Server {
+	return httptest.NewServer(wsTestHandler(t, handle))
+}
+
+func wsTestHandler(t *testing.T, handle func(c *wsTestConn, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Sec-WebSocket-Protocol") != graphqlTransportWS {
+			http.Error(w, "unsupported subprotocol", http.StatusBadRequest)
+			return
+		}
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			Subprotocols: []string{graphqlTransportWS},
+		})
+		if err != nil {
+			t.Errorf("accept failed: %v", err)
+			return
+		}
+		defer conn.CloseNow()
+		handle(&wsTestConn{wsConn: &wsConn{conn: conn}, ctx: r.Context()}, r)
+	}
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func readWSMessage(t *testing.T, c *wsTestConn) *wsIncomingMessage {
+	msg := &wsIncomingMessage{}
+	if err := c.readJSON(c.ctx, 5*time.Second, msg); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			t.Errorf("invalid message: %v", err)
+		}
+		return &wsIncomingMessage{Type: "closed"}
+	}
+	return msg
+}
+
+// ackSubscription acks the connection and returns the subscribe payload.
+func ackSubscription(t *testing.T, c *wsTestConn) graphqlRequest {
+	if msg := readWSMessage(t, c); msg.Type != "connection_init" {
+		t.Errorf("Expected connection_init, got %s", msg.Type)
+	}
+	c.writeJSON(wsMessage{Type: "connection_ack"})
+	msg := readWSMessage(t, c)
+	if msg.Type != "subscribe" || msg.ID != "1" {
+		t.Errorf("Expected subscribe with id 1, got %s %s", msg.Type, msg.ID)
+	}
+	var req graphqlRequest
+	json.Unmarshal(msg.Payload, &req)
+	return req
+}
+
+func sendNext(c *wsTestConn, data string) {
+	c.writeJSON(wsMessage{
+		ID:      "1",
+		Type:    "next",
+		Payload: json.RawMessage(data),
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	completed := make(chan struct{})
+	server := newWSTestServer(t, func(c *wsTestConn, r *http.Request) {
+		if r.Header.Get("x-hasura-admin-secret") != "secret" {
+			t.Errorf("Expected handshake header, got %v", r.Header)
+		}
+		msg := readWSMessage(t, c)
+		var payload struct {
+			Headers map[string]string `json:"headers"`
+		}
+		json.Unmarshal(msg.Payload, &payload)
+		if msg.Type != "connection_init" || payload.Headers["x-hasura-admin-secret"] != "secret" {
+			t.Errorf("Expected connection_init with headers, got %s %s", msg.Type, msg.Payload)
+		}
+		c.writeJSON(wsMessage{Type: "connection_ack"})
+
+		msg = readWSMessage(t, c)
+		var req graphqlRequest
+		json.Unmarshal(msg.Payload, &req)
+		expectedQuery := `subscription get_sub_test($sub_test_Name: String!) {
+sub_test(where: {name: {_eq: $sub_test_Name}}) {
+id
+}
+}`
+		if msg.Type != "subscribe" || req.Query != expectedQuery || req.Variables["sub_test_Name"] != "abc" {
+			t.Errorf("Unexpected subscribe message %s", msg.Payload)
+		}
+
+		c.writeJSON(wsMessage{Type: "ping"})
+		if msg := readWSMessage(t, c); msg.Type != "pong" {
+			t.Errorf("Expected pong, got %s", msg.Type)
+		}
+		sendNext(c, `{"data": {"sub_test": [{"id": 1}]}}`)
+		sendNext(c, `{"data": {"sub_test": [{"id": 1}, {"id": 2}]}}`)
+		if msg := readWSMessage(t, c); msg.Type != "complete" {
+			t.Errorf("Expected complete, got %s", msg.Type)
+		}
+		close(completed)
+	})
+	defer server.Close()
+
+	client := NewSubscriptionClient(wsURL(server), &SubscriptionClientOpts{
+		Headers: map[string]string{"x-hasura-admin-secret": "secret"},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, errs := Get[subTestModel]().Where(
+		Eq[subTestModel](Field[subTestModel]{
+			Name:  "name",
+			Value: QueryVar("sub_test_Name", StringVar[string]("abc")),
+		}),
+	).Select(subTestModel_ID).Subscribe(ctx, client)
+
+	if rows := <-results; len(rows) != 1 || rows[0].ID != 1 {
+		t.Errorf("Unexpected first result %+v", rows)
+	}
+	if rows := <-results; len(rows) != 2 || rows[1].ID != 2 {
+		t.Errorf("Unexpected second result %+v", rows)
+	}
+	cancel()
+	if _, ok := <-results; ok {
+		t.Errorf("Expected results to be closed")
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	select {
+	case <-completed:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the subscription to be completed")
+	}
+}
+
+func TestSubscribeReconnect(t *testing.T) {
+	var conns int32
+	server := newWSTestServer(t, func(c *wsTestConn, r *http.Request) {
+		switch atomic.AddInt32(&conns, 1) {
+		case 1:
+			ackSubscription(t, c)
+			sendNext(c, `{"data": {"sub_test_aggregate": {"aggregate": {"count": 1}}}}`)
+			// drop the connection without closing it
+		case 2:
+			c.close(1011, "internal error")
+		default:
+			ackSubscription(t, c)
+			sendNext(c, `{"data": {"sub_test_aggregate": {"aggregate": {"count": 2}}}}`)
+			c.writeJSON(wsMessage{ID: "1", Type: "complete"})
+			readWSMessage(t, c)
+		}
+	})
+	defer server.Close()
+
+	client := NewSubscriptionClient(wsURL(server), &SubscriptionClientOpts{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	})
+	results, errs := Aggregate[subTestModel]().Select(Count[subTestModel]()).Subscribe(context.Background(), client)
+
+	var counts []int
+	for result := range results {
+		counts = append(counts, result.Aggregate.Count)
+	}
+	if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+		t.Errorf("Expected counts [1 2], got %v", counts)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&conns); n != 3 {
+		t.Errorf("Expected 3 connections, got %d", n)
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	server := newWSTestServer(t, func(c *wsTestConn, r *http.Request) {
+		ackSubscription(t, c)
+		c.writeJSON(wsMessage{
+			ID:      "1",
+			Type:    "error",
+			Payload: json.RawMessage(`[{"message": "field 'nme' not found", "extensions": {"code": "validation-failed"}}]`),
+		})
+		readWSMessage(t, c)
+	})
+	defer server.Close()
+
+	results, errs := Get[subTestModel]().Select(subTestModel_ID).Subscribe(context.Background(), NewSubscriptionClient(wsURL(server), nil))
+	if _, ok := <-results; ok {
+		t.Errorf("Expected no results")
+	}
+	if err := <-errs; !errors.Is(err, ErrValidationFailed) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	rejecting := newWSTestServer(t, func(c *wsTestConn, r *http.Request) {
+		readWSMessage(t, c)
+		c.close(4403, "Forbidden")
+	})
+	defer rejecting.Close()
+
+	results, errs = Get[subTestModel]().Select(subTestModel_ID).Subscribe(context.Background(), NewSubscriptionClient(wsURL(rejecting), nil))
+	if _, ok := <-results; ok {
+		t.Errorf("Expected no results")
+	}
+	if err := <-errs; !errors.Is(err, ErrSubscriptionRejected) {
+		t.Errorf("Expected rejected subscription, got %v", err)
+	}
+
+	var handshakes int32
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&handshakes, 1)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	client := NewSubscriptionClient(wsURL(unauthorized), &SubscriptionClientOpts{MinBackoff: time.Millisecond})
+	results, errs = Get[subTestModel]().Select(subTestModel_ID).Subscribe(context.Background(), client)
+	if _, ok := <-results; ok {
+		t.Errorf("Expected no results")
+	}
+	if err := <-errs; !errors.Is(err, ErrHandshakeFailed) || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected failed handshake, got %v", err)
+	}
+	if n := atomic.LoadInt32(&handshakes); n != 1 {
+		t.Errorf("Expected the handshake to not be retried, got %d handshakes", n)
+	}
+
+	results, errs = Get[subTestModel]().Select(subTestModel_ID).Subscribe(context.Background(), NewSubscriptionClient("ftp://localhost", nil))
+	if _, ok := <-results; ok {
+		t.Errorf("Expected no results")
+	}
+	if err := <-errs; !errors.Is(err, ErrHandshakeFailed) {
+		t.Errorf("Expected failed handshake, got %v", err)
+	}
+}
+
+func TestSubscribeHandshakeRetry(t *testing.T) {
+	var handshakes int32
+	upgrade := wsTestHandler(t, func(c *wsTestConn, r *http.Request) {
+		ackSubscription(t, c)
+		sendNext(c, `{"data": {"sub_test": [{"id": 1}]}}`)
+		readWSMessage(t, c)
+	})
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&handshakes, 1) <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		upgrade(w, r)
+	}))
+	defer unavailable.Close()
+
+	client := NewSubscriptionClient(wsURL(unavailable), &SubscriptionClientOpts{MinBackoff: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, errs := Get[subTestModel]().Select(subTestModel_ID).Subscribe(ctx, client)
+	select {
+	case rows := <-results:
+		if len(rows) != 1 || rows[0].ID != 1 {
+			t.Errorf("Unexpected result %+v", rows)
+		}
+	case err := <-errs:
+		t.Fatalf("Expected the 503 handshakes to be retried, got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a result")
+	}
+	if n := atomic.LoadInt32(&handshakes); n != 3 {
+		t.Errorf("Expected 3 handshakes, got %d", n)
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	var conns int32
+	queries := make(chan string, 2)
+	server := newWSTestServer(t, func(c *wsTestConn, r *http.Request) {
+		req := ackSubscription(t, c)
+		queries <- req.Query
+		if atomic.AddInt32(&conns, 1) == 1 {
+			sendNext(c, `{"data": {"sub_test_stream": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]}}`)
+			return
+		}
+		sendNext(c, `{"data": {"sub_test_stream": [{"id": 3, "name": "c"}]}}`)
+		c.writeJSON(wsMessage{ID: "1", Type: "complete"})
+		readWSMessage(t, c)
+	})
+	defer server.Close()
+
+	client := NewSubscriptionClient(wsURL(server), &SubscriptionClientOpts{
+		MinBackoff: time.Millisecond,
+	})
+	s := Stream[subTestModel](Field[subTestModel]{Name: "id", Value: 0}).BatchSize(2).Select(subTestModel_Name).Subscribe(context.Background(), client)
+
+	var ids []int
+	for batch := range s.Batches {
+		for _, row := range batch {
+			ids = append(ids, row.ID)
+		}
+		if cursor := s.Cursor(); cursor.Value != ids[len(ids)-1] {
+			t.Errorf("Expected cursor id %d once the batch is received, got %v", ids[len(ids)-1], cursor.Value)
+		}
+	}
+	if err := <-s.Errors; err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("Expected ids [1 2 3], got %v", ids)
+	}
+
+	expected := `subscription stream_sub_test {
+sub_test_stream(batch_size: 2, cursor: {initial_value: {id: %s}, ordering: ASC}) {
+name
+id
+}
+}`
+	if q := <-queries; q != fmt.Sprintf(expected, "0") {
+		t.Errorf("Unexpected first query %s", q)
+	}
+	if q := <-queries; q != fmt.Sprintf(expected, "2") {
+		t.Errorf("Unexpected resumed query %s", q)
+	}
+	if cursor := s.Cursor(); cursor.Name != "id" || cursor.Value != 3 {
+		t.Errorf("Expected cursor id 3, got %+v", cursor)
+	}
+}
+
+func TestStreamCursorZeroValue(t *testing.T) {
+	type row struct {
+		ID   int    `json:"id,omitempty"`
+		Name string `json:"name"`
+	}
+	type embedding struct {
+		row
+	}
+	s := &StreamSubscription[subTestModel]{
+		sq: Stream[subTestModel](Field[subTestModel]{Name: "id", Value: 5}).Desc().Select(subTestModel_ID),
+	}
+	undo := s.advance([]subTestModel{{ID: 1}, {ID: 0}})
+	if cursor := s.Cursor(); cursor.Value != 0 {
+		t.Errorf("Expected cursor id 0, got %v", cursor.Value)
+	}
+	undo()
+	if cursor := s.Cursor(); cursor.Value != 5 {
+		t.Errorf("Expected cursor id 5 once undone, got %v", cursor.Value)
+	}
+
+	if v, ok := fieldByJSONName(reflect.ValueOf(embedding{row{Name: "a"}}), "id"); !ok || v.Interface() != 0 {
+		t.Errorf("Expected the omitted id of the embedded row, got %v", v)
+	}
+	if _, ok := fieldByJSONName(reflect.ValueOf(row{}), "ID"); ok {
+		t.Errorf("Expected no field encoded as ID")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -111,11 +110,7 @@ func (aq ActionQuery[M]) Exec(client *eywa.Client) (*M, error) {
 	}
 
 	if len(respObj.Errors) > 0 {
-		gqlErrs := make([]error, 0, len(respObj.Errors))
-		for _, e := range respObj.Errors {
-			gqlErrs = append(gqlErrs, errors.New(e.Message))
-		}
-		return nil, errors.Join(gqlErrs...)
+		return nil, &eywa.GraphQLErrors{Errors: respObj.Errors}
 	}
 
 	return respObj.Data[aq.aq.ModelName], nil
@@ -21,11 +21,49 @@ type UpdateQueryBuilder[M Model] struct {
 
 func (uq UpdateQueryBuilder[M]) Set(fields ...Field[M]) UpdateQueryBuilder[M] {
 	uq.set = &set[M]{FieldArray[M](fields)}
-	for _, f := range fields {
-		if var_, ok := f.GetRawValue().(queryVar); ok {
-			uq.queryVars = append(uq.queryVars, var_)
-		}
-	}
+	return uq
+}
+
+// Inc increments the numeric fields by the values of the given fields. If a
+// field is given more than once, the last value is used.
+func (uq UpdateQueryBuilder[M]) Inc(fields ...Field[M]) UpdateQueryBuilder[M] {
+	uq.inc = newUpdateOperator("_inc", fields)
+	return uq
+}
+
+// Append appends the values of the given fields to the jsonb fields. If a
+// field is given more than once, the last value is used.
+func (uq UpdateQueryBuilder[M]) Append(fields ...Field[M]) UpdateQueryBuilder[M] {
+	uq.append = newUpdateOperator("_append", jsonbFields(fields))
+	return uq
+}
+
+// Prepend prepends the values of the given fields to the jsonb fields. If a
+// field is given more than once, the last value is used.
+func (uq UpdateQueryBuilder[M]) Prepend(fields ...Field[M]) UpdateQueryBuilder[M] {
+	uq.prepend = newUpdateOperator("_prepend", jsonbFields(fields))
+	return uq
+}
+
+// DeleteKey deletes the top level key from the jsonb field. It can be called
+// for every field to update, calling it again for a field replaces its key.
+func (uq UpdateQueryBuilder[M]) DeleteKey(field FieldName[M], key string) UpdateQueryBuilder[M] {
+	uq.deleteKey = uq.deleteKey.add("_delete_key", Field[M]{Name: string(field), Value: key, Type: "String"})
+	return uq
+}
+
+// DeleteElem deletes the array element at index from the jsonb field. Negative
+// indexes count from the end of the array. Like DeleteKey, calling it again
+// for a field replaces its index.
+func (uq UpdateQueryBuilder[M]) DeleteElem(field FieldName[M], index int) UpdateQueryBuilder[M] {
+	uq.deleteElem = uq.deleteElem.add("_delete_elem", Field[M]{Name: string(field), Value: index, Type: "Int"})
+	return uq
+}
+
+// DeleteAtPath deletes the element at path from the jsonb field. Like
+// DeleteKey, calling it again for a field replaces its path.
+func (uq UpdateQueryBuilder[M]) DeleteAtPath(field FieldName[M], path ...string) UpdateQueryBuilder[M] {
+	uq.deletePath = uq.deletePath.add("_delete_at_path", Field[M]{Name: string(field), Value: path, Type: "[String!]"})
 	return uq
 }
 
@@ -35,16 +73,20 @@ func (uq UpdateQueryBuilder[M]) Where(w *WhereExpr) UpdateQueryBuilder[M] {
 }
 
 func (uq *UpdateQueryBuilder[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq *UpdateQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
 	if uq.where == nil {
 		uq.where = &where{Not(&WhereExpr{})}
 	}
 	return fmt.Sprintf(
 		"update_%s",
-		uq.QuerySkeleton.MarshalGQL(),
+		uq.QuerySkeleton.marshalGQL(vs),
 	)
 }
 
-func (uq UpdateQueryBuilder[M]) Select(field FieldName[M], fields ...FieldName[M]) UpdateQuery[M] {
+func (uq UpdateQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) UpdateQuery[M] {
 	return UpdateQuery[M]{
 		uq:     &uq,
 		fields: append(fields, field),
@@ -53,32 +95,37 @@ func (uq UpdateQueryBuilder[M]) Select(field FieldName[M], fields ...FieldName[M
 
 type UpdateQuery[M Model] struct {
 	uq     *UpdateQueryBuilder[M]
-	fields []FieldName[M]
+	fields []Selection[M]
 }
 
 func (uq UpdateQuery[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq UpdateQuery[M]) marshalGQL(vs *queryVarSet) string {
 	return fmt.Sprintf(
 		"%s {\nreturning {\n%s\n}\n}",
-		uq.uq.MarshalGQL(),
-		FieldNameArray[M](uq.fields).MarshalGQL(),
+		uq.uq.marshalGQL(vs),
+		selectionArray[M](uq.fields).marshalGQL(vs),
 	)
 }
 
-func (uq UpdateQuery[M]) Query() string {
+func (uq UpdateQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := uq.marshalGQL(vs)
 	return fmt.Sprintf(
 		"mutation update_%s%s {\n%s\n}",
 		uq.uq.ModelName,
-		uq.uq.queryVars.MarshalGQL(),
-		uq.MarshalGQL(),
+		vs.MarshalGQL(),
+		body,
 	)
 }
 
+func (uq UpdateQuery[M]) Query() string {
+	return operationQuery(uq)
+}
+
 func (uq UpdateQuery[M]) Variables() map[string]interface{} {
-	vars := map[string]interface{}{}
-	for _, var_ := range uq.uq.queryVars {
-		vars[var_.name] = var_.value.Value()
-	}
-	return vars
+	return operationVariables(uq)
 }
 
 func (uq UpdateQuery[M]) Exec(client *Client) ([]M, error) {
@@ -86,24 +133,182 @@ func (uq UpdateQuery[M]) Exec(client *Client) ([]M, error) {
 }
 
 func (uq UpdateQuery[M]) ExecWithContext(ctx context.Context, client *Client) ([]M, error) {
-	respBytes, err := client.Do(ctx, uq)
-	if err != nil {
+	resp, err := execQuery[M, *MutationResponse[M]](ctx, client, uq, fmt.Sprintf("update_%s", uq.uq.ModelName))
+	if err != nil || resp == nil {
 		return nil, err
 	}
+	return resp.Returning, nil
+}
+
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields.
+func (uq UpdateQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return uq.ExecIntoWithContext(context.Background(), client, dst)
+}
 
-	type mutationReturning struct {
-		Returning []M `json:"returning"`
+func (uq UpdateQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	var resp struct {
+		Returning json.RawMessage `json:"returning"`
 	}
-	type graphqlResponse struct {
-		Data   map[string]mutationReturning `json:"data"`
-		Errors []GraphQLError               `json:"errors"`
+	if err := execQueryInto[M](ctx, client, uq, uq.mutationRoot(), &resp); err != nil || len(resp.Returning) == 0 {
+		return err
 	}
+	return json.Unmarshal(resp.Returning, dst)
+}
 
-	respObj := graphqlResponse{}
+func (uq UpdateQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("update_%s", uq.uq.ModelName)
+}
+
+func (uq UpdateQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
 
-	err = json.NewDecoder(respBytes).Decode(&respObj)
-	if err != nil {
+func (uq UpdateQuery[M]) decodeMutationResult(data json.RawMessage) ([]M, error) {
+	resp, err := decodeResult[*MutationResponse[M]](data)
+	if err != nil || resp == nil {
 		return nil, err
 	}
-	return respObj.Data[fmt.Sprintf("update_%s", uq.uq.ModelName)].Returning, nil
+	return resp.Returning, nil
+}
+
+func UpdateByPk[M Model, MP ModelPtr[M]](pk PrimaryKey[M]) UpdateByPkQueryBuilder[M] {
+	return UpdateByPkQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				pkColumns: &pkColumns[M]{FieldArray[M](pk)},
+			},
+		},
+	}
+}
+
+type UpdateByPkQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (uq UpdateByPkQueryBuilder[M]) Set(fields ...Field[M]) UpdateByPkQueryBuilder[M] {
+	uq.set = &set[M]{FieldArray[M](fields)}
+	return uq
+}
+
+// Inc is like UpdateQueryBuilder.Inc, for the row.
+func (uq UpdateByPkQueryBuilder[M]) Inc(fields ...Field[M]) UpdateByPkQueryBuilder[M] {
+	uq.inc = newUpdateOperator("_inc", fields)
+	return uq
+}
+
+// Append is like UpdateQueryBuilder.Append, for the row.
+func (uq UpdateByPkQueryBuilder[M]) Append(fields ...Field[M]) UpdateByPkQueryBuilder[M] {
+	uq.append = newUpdateOperator("_append", jsonbFields(fields))
+	return uq
+}
+
+// Prepend is like UpdateQueryBuilder.Prepend, for the row.
+func (uq UpdateByPkQueryBuilder[M]) Prepend(fields ...Field[M]) UpdateByPkQueryBuilder[M] {
+	uq.prepend = newUpdateOperator("_prepend", jsonbFields(fields))
+	return uq
+}
+
+// DeleteKey is like UpdateQueryBuilder.DeleteKey, for the row.
+func (uq UpdateByPkQueryBuilder[M]) DeleteKey(field FieldName[M], key string) UpdateByPkQueryBuilder[M] {
+	uq.deleteKey = uq.deleteKey.add("_delete_key", Field[M]{Name: string(field), Value: key, Type: "String"})
+	return uq
+}
+
+// DeleteElem is like UpdateQueryBuilder.DeleteElem, for the row.
+func (uq UpdateByPkQueryBuilder[M]) DeleteElem(field FieldName[M], index int) UpdateByPkQueryBuilder[M] {
+	uq.deleteElem = uq.deleteElem.add("_delete_elem", Field[M]{Name: string(field), Value: index, Type: "Int"})
+	return uq
+}
+
+// DeleteAtPath is like UpdateQueryBuilder.DeleteAtPath, for the row.
+func (uq UpdateByPkQueryBuilder[M]) DeleteAtPath(field FieldName[M], path ...string) UpdateByPkQueryBuilder[M] {
+	uq.deletePath = uq.deletePath.add("_delete_at_path", Field[M]{Name: string(field), Value: path, Type: "[String!]"})
+	return uq
+}
+
+func (uq *UpdateByPkQueryBuilder[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq *UpdateByPkQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"update_%s_by_pk%s",
+		uq.QuerySkeleton.ModelName,
+		uq.queryArgs.marshalGQL(vs),
+	)
+}
+
+func (uq UpdateByPkQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) UpdateByPkQuery[M] {
+	return UpdateByPkQuery[M]{
+		uq:     &uq,
+		fields: append(fields, field),
+	}
+}
+
+type UpdateByPkQuery[M Model] struct {
+	uq     *UpdateByPkQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (uq UpdateByPkQuery[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq UpdateByPkQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\n%s\n}",
+		uq.uq.marshalGQL(vs),
+		selectionArray[M](uq.fields).marshalGQL(vs),
+	)
+}
+
+func (uq UpdateByPkQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := uq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation update_%s_by_pk%s {\n%s\n}",
+		uq.uq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (uq UpdateByPkQuery[M]) Query() string {
+	return operationQuery(uq)
+}
+
+func (uq UpdateByPkQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(uq)
+}
+
+func (uq UpdateByPkQuery[M]) Exec(client *Client) (*M, error) {
+	return uq.ExecWithContext(context.Background(), client)
+}
+
+// ExecWithContext returns nil without an error if no row exists for the primary key.
+func (uq UpdateByPkQuery[M]) ExecWithContext(ctx context.Context, client *Client) (*M, error) {
+	return execQuery[M, *M](ctx, client, uq, fmt.Sprintf("update_%s_by_pk", uq.uq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of M, eg. into a
+// struct with fields for aliased fields.
+func (uq UpdateByPkQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return uq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (uq UpdateByPkQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, uq, uq.mutationRoot(), dst)
+}
+
+func (uq UpdateByPkQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("update_%s_by_pk", uq.uq.ModelName)
+}
+
+func (uq UpdateByPkQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (uq UpdateByPkQuery[M]) decodeMutationResult(data json.RawMessage) (*M, error) {
+	return decodeResult[*M](data)
 }
@@ -0,0 +1,122 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// UpdateMany applies the updates, each with its own where and update operators,
+// in a single transaction. The Select of the update builders is not used, eg.
+//
+//	UpdateMany[User](
+//		Update[User]().Where(Eq(User_IDField(1))).Set(User_NameField("a")),
+//		Update[User]().Where(Eq(User_IDField(2))).Inc(User_AgeField(1)),
+//	).Select(User_ID)
+func UpdateMany[M Model, MP ModelPtr[M]](update UpdateQueryBuilder[M], rest ...UpdateQueryBuilder[M]) UpdateManyQueryBuilder[M] {
+	entries := make([]queryArgs[M], 0, len(rest)+1)
+	for _, uq := range append([]UpdateQueryBuilder[M]{update}, rest...) {
+		if uq.where == nil {
+			uq.where = &where{Not(&WhereExpr{})}
+		}
+		entries = append(entries, uq.queryArgs)
+	}
+	return UpdateManyQueryBuilder[M]{
+		QuerySkeleton: QuerySkeleton[M]{
+			ModelName: (*new(M)).ModelName(),
+			queryArgs: queryArgs[M]{
+				updates: &updates[M]{entries},
+			},
+		},
+	}
+}
+
+type UpdateManyQueryBuilder[M Model] struct {
+	QuerySkeleton[M]
+}
+
+func (uq *UpdateManyQueryBuilder[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq *UpdateManyQueryBuilder[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"update_%s_many%s",
+		uq.QuerySkeleton.ModelName,
+		uq.queryArgs.marshalGQL(vs),
+	)
+}
+
+func (uq UpdateManyQueryBuilder[M]) Select(field Selection[M], fields ...Selection[M]) UpdateManyQuery[M] {
+	return UpdateManyQuery[M]{
+		uq:     &uq,
+		fields: append(fields, field),
+	}
+}
+
+type UpdateManyQuery[M Model] struct {
+	uq     *UpdateManyQueryBuilder[M]
+	fields []Selection[M]
+}
+
+func (uq UpdateManyQuery[M]) MarshalGQL() string {
+	return uq.marshalGQL(nil)
+}
+
+func (uq UpdateManyQuery[M]) marshalGQL(vs *queryVarSet) string {
+	return fmt.Sprintf(
+		"%s {\naffected_rows\nreturning {\n%s\n}\n}",
+		uq.uq.marshalGQL(vs),
+		selectionArray[M](uq.fields).marshalGQL(vs),
+	)
+}
+
+func (uq UpdateManyQuery[M]) marshalOperation(vs *queryVarSet) string {
+	body := uq.marshalGQL(vs)
+	return fmt.Sprintf(
+		"mutation update_%s_many%s {\n%s\n}",
+		uq.uq.ModelName,
+		vs.MarshalGQL(),
+		body,
+	)
+}
+
+func (uq UpdateManyQuery[M]) Query() string {
+	return operationQuery(uq)
+}
+
+func (uq UpdateManyQuery[M]) Variables() map[string]interface{} {
+	return operationVariables(uq)
+}
+
+// Exec returns the affected rows and returned rows of every update, in the
+// order the updates were passed to UpdateMany.
+func (uq UpdateManyQuery[M]) Exec(client *Client) ([]MutationResponse[M], error) {
+	return uq.ExecWithContext(context.Background(), client)
+}
+
+func (uq UpdateManyQuery[M]) ExecWithContext(ctx context.Context, client *Client) ([]MutationResponse[M], error) {
+	return execQuery[M, []MutationResponse[M]](ctx, client, uq, fmt.Sprintf("update_%s_many", uq.uq.ModelName))
+}
+
+// ExecInto decodes the result of the query into dst, instead of MutationResponse, eg. into a
+// struct with fields for aliased fields.
+func (uq UpdateManyQuery[M]) ExecInto(client *Client, dst interface{}) error {
+	return uq.ExecIntoWithContext(context.Background(), client, dst)
+}
+
+func (uq UpdateManyQuery[M]) ExecIntoWithContext(ctx context.Context, client *Client, dst interface{}) error {
+	return execQueryInto[M](ctx, client, uq, uq.mutationRoot(), dst)
+}
+
+func (uq UpdateManyQuery[M]) mutationRoot() string {
+	return fmt.Sprintf("update_%s_many", uq.uq.ModelName)
+}
+
+func (uq UpdateManyQuery[M]) classifyError(e GraphQLError) error {
+	return classifyGraphQLError[M](e)
+}
+
+func (uq UpdateManyQuery[M]) decodeMutationResult(data json.RawMessage) ([]MutationResponse[M], error) {
+	return decodeResult[[]MutationResponse[M]](data)
+}
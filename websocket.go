@@ -0,0 +1,113 @@
+package eywa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// wsConn is a websocket connection carrying the messages of graphql
+// subscriptions.
+type wsConn struct {
+	conn *websocket.Conn
+}
+
+const wsMaxMessageSize = 32 << 20
+
+// dialWebSocket opens a websocket connection to endpoint, which may use the
+// ws, wss, http or https scheme, with the handshake request sent by
+// httpClient.
+func dialWebSocket(ctx context.Context, httpClient *http.Client, endpoint, subprotocol string, headers map[string]string) (*wsConn, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+	switch u.Scheme {
+	case "ws", "wss", "http", "https":
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrHandshakeFailed, u.Scheme)
+	}
+
+	header := http.Header{}
+	for k, v := range headers {
+		header.Add(k, v)
+	}
+	conn, resp, err := websocket.Dial(ctx, endpoint, &websocket.DialOptions{
+		HTTPClient:   httpClient,
+		HTTPHeader:   header,
+		Subprotocols: []string{subprotocol},
+	})
+	if err != nil {
+		if resp != nil && !retryableHandshakeStatus(resp.StatusCode) {
+			// the server answered, but refused or botched the upgrade.
+			return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+		}
+		return nil, err
+	}
+	if p := conn.Subprotocol(); p != subprotocol {
+		if err := conn.CloseNow(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: unsupported subprotocol %q", ErrHandshakeFailed, p)
+	}
+	conn.SetReadLimit(wsMaxMessageSize)
+	return &wsConn{conn: conn}, nil
+}
+
+// retryableHandshakeStatus reports whether a handshake answered with the
+// status may succeed later, eg. once a restarting server is back up.
+func retryableHandshakeStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// readJSON reads the next message into v, waiting at most timeout for it.
+// The connection is closed if the timeout expires.
+func (c *wsConn) readJSON(ctx context.Context, timeout time.Duration, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, msg, err := c.conn.Read(ctx)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(msg, v)
+}
+
+func (c *wsConn) writeJSON(ctx context.Context, v interface{}) error {
+	return wsjson.Write(ctx, c.conn, v)
+}
+
+// close closes the connection with the code and reason, after the closing
+// handshake with the peer. Closing a closed connection isn't an error.
+func (c *wsConn) close(code websocket.StatusCode, reason string) error {
+	return ignoreClosed(c.conn.Close(code, reason))
+}
+
+// closeNow closes the connection without the closing handshake.
+func (c *wsConn) closeNow() error {
+	return ignoreClosed(c.conn.CloseNow())
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// wsCloseStatus returns the close code and reason sent by the peer, if err is
+// the error of a connection it closed.
+func wsCloseStatus(err error) (websocket.StatusCode, string, bool) {
+	var closeErr websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return 0, "", false
+	}
+	return closeErr.Code, closeErr.Reason, true
+}